@@ -0,0 +1,190 @@
+package gitw
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// BlameCommit the commit info of the blame lines. it is shared by the lines of the same commit.
+type BlameCommit struct {
+	Hash      string
+	Author    Person
+	Committer Person
+	// Summary the subject of the commit message
+	Summary string
+	// Previous commit hash and filename, is empty on the commit has no parent.
+	Previous, PreviousFile string
+	// Boundary the commit is the boundary of the blame range
+	Boundary bool
+	// Filename of the file in the commit
+	Filename string
+}
+
+// IsUncommitted the lines are not committed yet
+func (c *BlameCommit) IsUncommitted() bool {
+	return strings.Trim(c.Hash, "0") == ""
+}
+
+// BlameLine a line record of git blame
+type BlameLine struct {
+	Commit *BlameCommit
+	// OrigLine the line number in the original file of the commit
+	OrigLine int
+	// FinalLine the line number in the final file
+	FinalLine int
+	// OrigFile the filename in the commit
+	OrigFile string
+	// Content of the line
+	Content string
+}
+
+// ParseBlame parse the output of: git blame --porcelain
+//
+// see https://git-scm.com/docs/git-blame#_the_porcelain_format
+func ParseBlame(out string) ([]*BlameLine, error) {
+	var lines []*BlameLine
+	var cur *BlameLine
+	commits := make(map[string]*BlameCommit)
+
+	for i, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+
+		// content line, end of the line record
+		if line[0] == '\t' {
+			if cur == nil {
+				return nil, errorx.Rawf("blame line %d: content without header", i+1)
+			}
+			if cur.OrigFile == "" {
+				cur.OrigFile = cur.Commit.Filename
+			}
+
+			cur.Content = line[1:]
+			lines = append(lines, cur)
+			cur = nil
+			continue
+		}
+
+		// header line: <hash> <orig-line> <final-line> [<num-lines>]
+		if cur == nil {
+			ss := strings.Fields(line)
+			if len(ss) < 3 || len(ss[0]) < 40 {
+				return nil, errorx.Rawf("blame line %d: invalid header %q", i+1, line)
+			}
+
+			cur = &BlameLine{}
+			var err1, err2 error
+			cur.OrigLine, err1 = strconv.Atoi(ss[1])
+			cur.FinalLine, err2 = strconv.Atoi(ss[2])
+			if err1 != nil || err2 != nil {
+				return nil, errorx.Rawf("blame line %d: invalid header %q", i+1, line)
+			}
+
+			if cur.Commit = commits[ss[0]]; cur.Commit == nil {
+				cur.Commit = &BlameCommit{Hash: ss[0]}
+				commits[ss[0]] = cur.Commit
+			}
+			continue
+		}
+
+		if err := cur.parseDetail(line); err != nil {
+			return nil, errorx.Rawf("blame line %d: %s", i+1, err.Error())
+		}
+	}
+
+	if cur != nil {
+		return nil, errorx.Raw("blame output is incomplete, missing content line")
+	}
+	return lines, nil
+}
+
+// parse the commit detail line. eg: "author inhere", "author-time 1704178800"
+func (bl *BlameLine) parseDetail(line string) error {
+	c := bl.Commit
+	key, val, _ := strings.Cut(line, " ")
+
+	var err error
+	switch key {
+	case "author":
+		c.Author.Name = val
+	case "author-mail":
+		c.Author.Email = strings.Trim(val, "<>")
+	case "author-time":
+		c.Author.When, err = parseBlameTime(val, c.Author.When)
+	case "author-tz":
+		c.Author.When, err = withBlameTZ(c.Author.When, val)
+	case "committer":
+		c.Committer.Name = val
+	case "committer-mail":
+		c.Committer.Email = strings.Trim(val, "<>")
+	case "committer-time":
+		c.Committer.When, err = parseBlameTime(val, c.Committer.When)
+	case "committer-tz":
+		c.Committer.When, err = withBlameTZ(c.Committer.When, val)
+	case "summary":
+		c.Summary = val
+	case "previous":
+		c.Previous, c.PreviousFile, _ = strings.Cut(val, " ")
+	case "boundary":
+		c.Boundary = true
+	case "filename":
+		// the commit maybe has multi filenames, so record it to the line
+		c.Filename, bl.OrigFile = val, val
+	}
+	return err
+}
+
+func parseBlameTime(val string, old time.Time) (time.Time, error) {
+	sec, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return old, errorx.Rawf("invalid time %q", val)
+	}
+	return time.Unix(sec, 0).In(old.Location()), nil
+}
+
+// set timezone to the time. eg: "+0800"
+func withBlameTZ(t time.Time, tz string) (time.Time, error) {
+	if len(tz) != 5 || (tz[0] != '+' && tz[0] != '-') {
+		return t, errorx.Rawf("invalid timezone %q", tz)
+	}
+
+	h, err1 := strconv.Atoi(tz[1:3])
+	m, err2 := strconv.Atoi(tz[3:])
+	if err1 != nil || err2 != nil {
+		return t, errorx.Rawf("invalid timezone %q", tz)
+	}
+
+	offset := h*3600 + m*60
+	if tz[0] == '-' {
+		offset = -offset
+	}
+	return t.In(time.FixedZone(tz, offset)), nil
+}
+
+// Blame get the line records of the file by git blame.
+//
+//   - rev is empty: blame the worktree file
+//   - lineRange is empty: blame all lines. see the -L option of git blame, eg: "10,20", "10,+5"
+//
+// Usage:
+//
+//	lines, err := repo.Blame("README.md", "HEAD", "1,10")
+func (r *Repo) Blame(path, rev, lineRange string) ([]*BlameLine, error) {
+	args := []string{"--porcelain"}
+	if lineRange != "" {
+		args = append(args, "-L", lineRange)
+	}
+	if rev != "" {
+		args = append(args, rev)
+	}
+
+	out, err := r.gw.Blame(append(args, "--", path)...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseBlame(out)
+}
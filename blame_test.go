@@ -0,0 +1,98 @@
+package gitw_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParseBlame(t *testing.T) {
+	out := `1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b 1 1 2
+author inhere
+author-mail <in.798@qq.com>
+author-time 1704178800
+author-tz +0800
+committer tom
+committer-mail <tom@example.com>
+committer-time 1704265200
+committer-tz -0130
+summary feat: add readme
+boundary
+filename README.md
+	# Gitw
+1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b 2 2
+	
+0000000000000000000000000000000000000000 3 3 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+author-time 1704351600
+author-tz +0000
+committer Not Committed Yet
+committer-mail <not.committed.yet>
+committer-time 1704351600
+committer-tz +0000
+summary Version of README.md from README.md
+previous 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b README.md
+filename README.md
+	new line
+`
+	lines, err := gitw.ParseBlame(out)
+	assert.NoErr(t, err)
+	assert.Len(t, lines, 3)
+
+	l := lines[0]
+	assert.Eq(t, 1, l.FinalLine)
+	assert.Eq(t, "README.md", l.OrigFile)
+	assert.Eq(t, "# Gitw", l.Content)
+	assert.Eq(t, "inhere <in.798@qq.com>", l.Commit.Author.String())
+	assert.Eq(t, "2024-01-02T15:00:00+08:00", l.Commit.Author.When.Format("2006-01-02T15:04:05Z07:00"))
+	assert.Eq(t, "2024-01-03T05:30:00-01:30", l.Commit.Committer.When.Format("2006-01-02T15:04:05Z07:00"))
+	assert.Eq(t, "feat: add readme", l.Commit.Summary)
+	assert.True(t, l.Commit.Boundary)
+	assert.False(t, l.Commit.IsUncommitted())
+
+	// shared commit
+	l = lines[1]
+	assert.Same(t, lines[0].Commit, l.Commit)
+	assert.Eq(t, 2, l.OrigLine)
+	assert.Eq(t, "README.md", l.OrigFile)
+	assert.Eq(t, "", l.Content)
+
+	l = lines[2]
+	assert.True(t, l.Commit.IsUncommitted())
+	assert.Eq(t, lines[0].Commit.Hash, l.Commit.Previous)
+	assert.Eq(t, "README.md", l.Commit.PreviousFile)
+
+	_, err = gitw.ParseBlame("abc 1 1\n\tline")
+	assert.Err(t, err)
+	_, err = gitw.ParseBlame(out[:len(out)-10])
+	assert.Err(t, err)
+}
+
+func TestRepo_Blame(t *testing.T) {
+	r := newTempRepo(t)
+	file := filepath.Join(r.Dir(), "hello.txt")
+	assert.NoErr(t, os.WriteFile(file, []byte("hello\nworld\n"), 0644))
+	assert.NoErr(t, r.Cmd("add", "hello.txt").Run())
+	assert.NoErr(t, r.Cmd("commit", "-q", "-m", "feat: add hello").Run())
+	assert.NoErr(t, os.WriteFile(file, []byte("hello\nworld\nnew\n"), 0644))
+
+	lines, err := r.Blame("hello.txt", "HEAD", "")
+	assert.NoErr(t, err)
+	assert.Len(t, lines, 2)
+	assert.Eq(t, "feat: add hello", lines[1].Commit.Summary)
+	assert.Eq(t, "world", lines[1].Content)
+
+	// worktree file with line range
+	lines, err = r.Blame("hello.txt", "", "2,3")
+	assert.NoErr(t, err)
+	assert.Len(t, lines, 2)
+	assert.Eq(t, 2, lines[0].FinalLine)
+	assert.True(t, lines[1].Commit.IsUncommitted())
+
+	_, err = r.Blame("not-exists.txt", "HEAD", "")
+	assert.Err(t, err)
+}
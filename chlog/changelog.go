@@ -116,6 +116,39 @@ func (c *Changelog) LogIsEmpty() bool {
 
 // FetchGitLog fetch log data by git log
 func (c *Changelog) FetchGitLog(sha1, sha2 string, moreArgs ...string) *Changelog {
+	c.SetLogText(c.newLogCmd(sha1, sha2, moreArgs).SafeOutput())
+	return c
+}
+
+// StreamGitLog fetch git log and parse each line on read, not buffer the whole log output.
+// After call it, can call Generate() for generate the changelog.
+func (c *Changelog) StreamGitLog(sha1, sha2 string, moreArgs ...string) error {
+	if c.parsed {
+		return nil
+	}
+
+	c.parsed = true
+	c.prepare()
+
+	logCmd := c.newLogCmd(sha1, sha2, moreArgs)
+	logCmd.Stderr = nil
+
+	var lineNum int
+	msgIDMap := make(map[string]int)
+
+	err := logCmd.Stream(func(line []byte) error {
+		lineNum++
+		c.parseLine(string(line), msgIDMap)
+		return nil
+	})
+
+	if err == nil && lineNum == 0 {
+		return ErrEmptyLogText
+	}
+	return err
+}
+
+func (c *Changelog) newLogCmd(sha1, sha2 string, moreArgs []string) *gitw.GitWrap {
 	logCmd := gitw.Log("--reverse").
 		Argf("--pretty=format:\"%s\"", c.cfg.LogFormat)
 
@@ -130,9 +163,7 @@ func (c *Changelog) FetchGitLog(sha1, sha2 string, moreArgs ...string) *Changelo
 	if sha1 != "" && sha2 != "" {
 		logCmd.Argf("%s...%s", sha1, sha2)
 	}
-
-	c.SetLogText(logCmd.SafeOutput())
-	return c
+	return logCmd
 }
 
 // prepare something
@@ -142,6 +173,11 @@ func (c *Changelog) prepare() {
 	}
 
 	c.ItemFilters = c.cfg.CreateFilters()
+
+	// ensure parser exists
+	if c.LineParser == nil {
+		c.LineParser = BuiltInParser
+	}
 }
 
 // -------------------------------------------------------------------
@@ -162,50 +198,48 @@ func (c *Changelog) Parse() (err error) {
 		return ErrEmptyLogText
 	}
 
-	// ensure parser exists
-	if c.LineParser == nil {
-		c.LineParser = BuiltInParser
-	}
-
-	parser := c.LineParser
 	msgIDMap := make(map[string]int)
-
 	for _, line := range strings.Split(str, "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+		c.parseLine(line, msgIDMap)
+	}
 
-		line = strings.Trim(line, "\"' ")
-		if line == "" {
-			continue
-		}
+	return
+}
 
-		// parse line
-		li := parser.Parse(line, c)
-		if li == nil {
-			continue
-		}
+// parse one log line and collect the log item.
+func (c *Changelog) parseLine(line string, msgIDMap map[string]int) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
 
-		// item filters
-		if !c.applyItemFilters(li) {
-			continue
-		}
+	line = strings.Trim(line, "\"' ")
+	if line == "" {
+		return
+	}
 
-		// remove repeat msg item
-		if c.cfg.RmRepeat {
-			msgID := strutil.Md5(li.Msg)
-			if _, ok := msgIDMap[msgID]; ok {
-				continue
-			}
+	// parse line
+	li := c.LineParser.Parse(line, c)
+	if li == nil {
+		return
+	}
+
+	// item filters
+	if !c.applyItemFilters(li) {
+		return
+	}
 
-			msgIDMap[msgID] = 1
+	// remove repeat msg item
+	if c.cfg.RmRepeat {
+		msgID := strutil.Md5(li.Msg)
+		if _, ok := msgIDMap[msgID]; ok {
+			return
 		}
 
-		c.logItems = append(c.logItems, li)
+		msgIDMap[msgID] = 1
 	}
 
-	return
+	c.logItems = append(c.logItems, li)
 }
 
 func (c *Changelog) applyItemFilters(li *LogItem) bool {
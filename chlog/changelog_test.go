@@ -0,0 +1,50 @@
+package chlog_test
+
+import (
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/gitw/chlog"
+	"github.com/gookit/gitw/gitwtest"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestChangelog_FetchGitLog_fakeRunner(t *testing.T) {
+	fr := gitwtest.NewFakeRunner().On(`log --reverse --pretty=format:"%H | %s" v0.1.0...v0.2.0`, `
+"3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61 | feat: add new option for dump"
+"73f824d0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61 | fix: fix the nil pointer error"
+"6fb8dcd0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61 | fix: fix the nil pointer error"
+`)
+
+	gitw.Std().WithRunner(fr)
+	defer gitw.RestStd()
+
+	cl := chlog.New().FetchGitLog("v0.1.0", "v0.2.0")
+	assert.False(t, cl.LogIsEmpty())
+	assert.NoErr(t, cl.Generate())
+
+	assert.Eq(t, 2, cl.LogCount())
+	str := cl.String()
+	assert.StrContains(t, str, "### Feature")
+	assert.StrContains(t, str, " - 3a6d3bb feat: add new option for dump")
+	assert.StrContains(t, str, " - 73f824d fix: fix the nil pointer error")
+}
+
+func TestChangelog_StreamGitLog_fakeRunner(t *testing.T) {
+	fr := gitwtest.NewFakeRunner().
+		On(`log --reverse --pretty=format:"%H | %s" --no-merges v0.1.0...v0.2.0`, `"3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61 | feat: add new option for dump"
+"73f824d0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61 | fix: fix the nil pointer error"`).
+		On(`log --reverse --pretty=format:"%H | %s" v0.2.0...v0.2.0`, "")
+
+	gitw.Std().WithRunner(fr)
+	defer gitw.RestStd()
+
+	cl := chlog.New()
+	assert.NoErr(t, cl.StreamGitLog("v0.1.0", "v0.2.0", "--no-merges"))
+	assert.NoErr(t, cl.Generate())
+	assert.Eq(t, 2, cl.LogCount())
+	assert.StrContains(t, cl.String(), " - 73f824d fix: fix the nil pointer error")
+
+	err := chlog.New().StreamGitLog("v0.2.0", "v0.2.0")
+	assert.ErrIs(t, err, chlog.ErrEmptyLogText)
+}
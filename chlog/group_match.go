@@ -3,6 +3,8 @@ package chlog
 import (
 	"strings"
 
+	"github.com/gookit/gitw/gitutil"
+	"github.com/gookit/goutil/arrutil"
 	"github.com/gookit/goutil/strutil"
 )
 
@@ -16,6 +18,8 @@ var DefaultMatcher = NewDefaultMatcher()
 type Rule struct {
 	// Name for group
 	Name string `json:"name" yaml:"name"`
+	// Types match the conventional commit type. eg: feat, fix
+	Types []string `json:"types" yaml:"types"`
 	// StartWiths message start withs string.
 	StartWiths []string `json:"start_withs" yaml:"start_withs"`
 	// Contains message should contain there are strings.
@@ -33,10 +37,14 @@ type RuleMatcher struct {
 func (m RuleMatcher) Match(msg string) string {
 	// remove prefix like ":sparkles:"
 	// eg ":sparkles: feat(dump): some message ..."
-	if strings.IndexByte(msg, ':') == 0 {
-		end := strings.IndexByte(msg[1:], ':')
-		if end > 1 {
-			msg = strings.TrimSpace(msg[end+2:])
+	_, msg = gitutil.StripGitmoji(msg)
+
+	// match by conventional commit type
+	if topics := gitutil.ParseCommitTopic(msg); len(topics) > 0 {
+		for _, rule := range m.Rules {
+			if len(rule.Types) > 0 && arrutil.StringsHas(rule.Types, strings.ToLower(topics[0])) {
+				return rule.Name
+			}
 		}
 	}
 
@@ -62,21 +70,25 @@ func NewDefaultMatcher() *RuleMatcher {
 		Rules: []Rule{
 			{
 				Name:       "Feature",
+				Types:      []string{"feat"},
 				StartWiths: []string{"feat", "new", "add"},
 				Contains:   []string{"feat:", "feat("},
 			},
 			{
 				Name:       "Refactor",
+				Types:      []string{"refactor"},
 				StartWiths: []string{"break", "refactor"},
 				Contains:   []string{"refactor:"},
 			},
 			{
 				Name:       "Update",
+				Types:      []string{"up", "update"},
 				StartWiths: []string{"up:", "up(", "update"},
 				Contains:   []string{"up:", "update:"},
 			},
 			{
 				Name:       "Fixed",
+				Types:      []string{"fix"},
 				StartWiths: []string{"bug", "close", "fix"},
 				Contains:   []string{"fix:", "bug:"},
 			},
@@ -15,4 +15,9 @@ func TestRuleMatcher_Match(t *testing.T) {
 	line = ":necktie: up(str): 更新字节工具方法并添加新的哈希工具方法"
 	m = chlog.DefaultMatcher.Match(line)
 	assert.Equal(t, "Update", m)
+
+	// match by conventional commit type
+	line = "✨ Fix(api)!: the feat: message contains other keywords"
+	m = chlog.DefaultMatcher.Match(line)
+	assert.Equal(t, "Fixed", m)
 }
@@ -0,0 +1,23 @@
+module commitlint
+
+go 1.23
+
+require (
+	github.com/goccy/go-yaml v1.19.2
+	github.com/gookit/color v1.6.1
+	github.com/gookit/gitw v1.0.0
+	github.com/gookit/goutil v0.8.0
+)
+
+require (
+	github.com/gookit/gsr v0.1.1 // indirect
+	github.com/gookit/slog v0.7.1 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/term v0.29.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+)
+
+replace github.com/gookit/gitw => ../../
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/gookit/color"
+	"github.com/gookit/gitw"
+	"github.com/gookit/gitw/commitlint"
+	"github.com/gookit/goutil/cflag"
+	"github.com/gookit/goutil/cliutil"
+	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/fsutil"
+)
+
+// Build-time variables injected via -ldflags
+var (
+	Version   = "dev"
+	GitHash   = "unknown"
+	BuildTime = "unknown"
+)
+
+// output formats
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+var opts = struct {
+	workdir    string
+	configFile string
+	// the commit message file, for use as commit-msg hook
+	editFile string
+	format   string
+	rev      string
+}{}
+
+var cfg = commitlint.NewDefaultConfig()
+var repo = gitw.NewRepo("./")
+var cmd *cflag.CFlags
+
+// quick run:
+//
+//	go run ./cmd/commitlint
+//	go run ./cmd/commitlint -h
+//
+// install to GOPATH/bin:
+//
+//	go install ./cmd/commitlint
+func main() {
+	cmd = cflag.New(func(c *cflag.CFlags) {
+		c.Version = Version
+		c.Desc = "Lint git commit messages by configurable rules. Build at: " + BuildTime
+	})
+
+	configCmd()
+
+	// exit with non-zero code on lint failed, the commit-msg hook depends on it.
+	if err := cmd.Parse(nil); err != nil {
+		color.Error.Println("ERROR:", err)
+		os.Exit(1)
+	}
+}
+
+func configCmd() {
+	cmd.StringVar(&opts.workdir, "workdir", "", "workdir for run, default is current workdir;;w")
+	cmd.StringVar(&opts.configFile, "config", ".commitlint.yml", "the YAML config file for lint rules;;c")
+	cmd.StringVar(&opts.editFile, "edit", "", "lint the commit message file, for use as commit-msg hook;;e")
+	cmd.StringVar(&opts.format, "format", formatText, "the output format. allow: text, json;;f")
+
+	cmd.AddArg("rev", "The commit revision or range to lint. eg: HEAD, v0.1.0..HEAD", false, gitw.TagHead)
+
+	cmd.Func = handle
+	cmd.Example = `
+  {{cmd}}
+  {{cmd}} HEAD~1
+  {{cmd}} v0.1.0..HEAD
+  {{cmd}} -f json main..HEAD
+  {{cmd}} -c .github/commitlint.yml -e .git/COMMIT_EDITMSG
+
+  # use as commit-msg hook, write to .git/hooks/commit-msg:
+  commitlint -e "$1"
+`
+}
+
+func handle(c *cflag.CFlags) error {
+	opts.rev = c.Arg("rev").String()
+	if opts.format != formatText && opts.format != formatJSON {
+		return errorx.Rawf("invalid output format %q, allow: text, json", opts.format)
+	}
+
+	if opts.workdir != "" {
+		if err := os.Chdir(opts.workdir); err != nil {
+			return err
+		}
+	}
+
+	if err := loadConfig(); err != nil {
+		return err
+	}
+
+	results, err := lint(commitlint.New(cfg))
+	if err != nil {
+		return err
+	}
+
+	rp := commitlint.NewReport(results...)
+	if opts.format == formatJSON {
+		err = rp.WriteJSON(os.Stdout)
+	} else {
+		err = rp.WriteText(os.Stdout)
+	}
+
+	if err == nil && !rp.Valid() {
+		err = errorx.Rawf("commit message lint failed with %d errors", rp.Errors)
+	}
+	return err
+}
+
+func loadConfig() error {
+	yml := fsutil.ReadExistFile(opts.configFile)
+	if len(yml) > 0 {
+		if err := yaml.Unmarshal(yml, cfg); err != nil {
+			return errorx.Wrapf(err, "invalid config file %q", opts.configFile)
+		}
+	}
+	return nil
+}
+
+func lint(l *commitlint.Linter) ([]*commitlint.Result, error) {
+	// lint message file
+	if opts.editFile != "" {
+		bs, err := os.ReadFile(opts.editFile)
+		if err != nil {
+			return nil, err
+		}
+		return []*commitlint.Result{l.Lint(commitlint.CleanMessage(string(bs)))}, nil
+	}
+
+	if strings.Contains(opts.rev, "..") {
+		if opts.format == formatText {
+			cliutil.Infoln("Lint commits:", opts.rev)
+		}
+		return l.LintRange(repo, opts.rev)
+	}
+
+	res, err := l.LintRevision(repo, opts.rev)
+	if err != nil {
+		return nil, err
+	}
+	return []*commitlint.Result{res}, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/gitw/commitlint"
+	"github.com/gookit/gitw/gitwtest"
+	"github.com/gookit/goutil/x/assert"
+)
+
+func TestLintMessageFile(t *testing.T) {
+	dir := t.TempDir()
+	msgFile := filepath.Join(dir, "COMMIT_EDITMSG")
+	err := os.WriteFile(msgFile, []byte("Fix: some bug\n\n# Please enter the commit message\n"), 0644)
+	assert.NoErr(t, err)
+
+	cfgFile := filepath.Join(dir, "commitlint.yml")
+	err = os.WriteFile(cfgFile, []byte("types: [feat, fix]\nheader_max_len: 10\nlevels:\n  header-max-length: warning\n"), 0644)
+	assert.NoErr(t, err)
+
+	oldOpts, oldCfg := opts, cfg
+	t.Cleanup(func() {
+		opts, cfg = oldOpts, oldCfg
+	})
+
+	opts.configFile = cfgFile
+	opts.editFile = msgFile
+	cfg = commitlint.NewDefaultConfig()
+	assert.NoErr(t, loadConfig())
+	assert.Eq(t, []string{"feat", "fix"}, cfg.Types)
+
+	results, err := lint(commitlint.New(cfg))
+	assert.NoErr(t, err)
+	assert.Len(t, results, 1)
+	assert.Eq(t, "Fix: some bug", results[0].Header)
+	assert.Eq(t, 1, results[0].ErrorCount())
+	assert.Eq(t, 1, results[0].WarningCount())
+}
+
+func TestLintRange(t *testing.T) {
+	r := gitwtest.NewTempRepo(t, "feat: initial commit")
+	assert.NoErr(t, r.Cmd("tag", "v0.1.0").Run())
+	assert.NoErr(t, r.Cmd("commit", "-q", "--allow-empty", "-m", "fix: second commit").Run())
+
+	oldRepo, oldOpts, oldCfg := repo, opts, cfg
+	t.Cleanup(func() {
+		repo, opts, cfg = oldRepo, oldOpts, oldCfg
+	})
+
+	repo = r
+	opts.editFile = ""
+	opts.format = formatJSON
+	opts.rev = "v0.1.0..HEAD"
+	cfg = commitlint.NewDefaultConfig()
+
+	results, err := lint(commitlint.New(cfg))
+	assert.NoErr(t, err)
+	assert.Len(t, results, 1)
+	assert.True(t, results[0].Valid())
+	assert.Eq(t, "fix: second commit", results[0].Header)
+}
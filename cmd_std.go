@@ -32,12 +32,12 @@ var GlobalFlags []string
 
 func gitCmd(args ...string) *GitWrap {
 	// with global flags
-	return std.New(GlobalFlags...).WithArgs(args)
+	return std.New(args...).WithGlobalFlags(GlobalFlags...)
 }
 
 func cmdWithArgs(subCmd string, args ...string) *GitWrap {
 	// with global flags
-	return std.Cmd(subCmd, GlobalFlags...).WithArgs(args)
+	return std.Cmd(subCmd, args...).WithGlobalFlags(GlobalFlags...)
 }
 
 func newStd() *GitWrap {
@@ -56,24 +56,24 @@ func newStd() *GitWrap {
 // -------------------------------------------------
 
 // Branch command of git
-func Branch(args ...string) *GitWrap { return std.Branch(args...) }
+func Branch(args ...string) *GitWrap { return cmdWithArgs("branch", args...) }
 
 // Log command of git
 //
 // Usage: Log("-2").OutputLines()
-func Log(args ...string) *GitWrap { return std.Log(args...) }
+func Log(args ...string) *GitWrap { return cmdWithArgs("log", args...) }
 
 // RevList command of git
-func RevList(args ...string) *GitWrap { return std.RevList(args...) }
+func RevList(args ...string) *GitWrap { return cmdWithArgs("rev-list", args...) }
 
 // Remote command of git
-func Remote(args ...string) *GitWrap { return std.Remote(args...) }
+func Remote(args ...string) *GitWrap { return cmdWithArgs("remote", args...) }
 
 // Show command of git
-func Show(args ...string) *GitWrap { return std.Show(args...) }
+func Show(args ...string) *GitWrap { return cmdWithArgs("show", args...) }
 
 // Tag command of git
 //
 // Usage:
 // 	Tag("-l").OutputLines()
-func Tag(args ...string) *GitWrap { return std.Tag(args...) }
+func Tag(args ...string) *GitWrap { return cmdWithArgs("tag", args...) }
@@ -2,11 +2,10 @@ package gitw
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/fsutil"
 	"github.com/gookit/goutil/sysutil/cmdr"
 )
 
@@ -23,50 +22,15 @@ func Version() (string, error) {
 	return cmdr.FirstLine(output), nil
 }
 
-var cachedDir string
-
-// DataDir get .git dir name. eg: ".git"
+// DataDir get .git data dir path. eg: "/path/to/repo/.git"
+//
+// support the linked worktree, submodule, bare repo and GIT_DIR. see ResolveGitDir()
 func DataDir() (string, error) {
-	if cachedDir != "" {
-		return cachedDir, nil
-	}
-
-	// git rev-parse -q --git-dir
-	dirCmd := gitCmd("rev-parse", "-q", "--git-dir")
-	dirCmd.Stderr = nil
-	output, err := dirCmd.Output()
+	dirs, err := gitCmd().ResolveGitDir()
 	if err != nil {
-		return "", errorx.Raw("not a git repository (or any of the parent directories): .git")
+		return "", err
 	}
-
-	var chdir string
-	for i, flag := range GlobalFlags {
-		if flag == "-C" {
-			dir := GlobalFlags[i+1]
-			if filepath.IsAbs(dir) {
-				chdir = dir
-			} else {
-				chdir = filepath.Join(chdir, dir)
-			}
-		}
-	}
-
-	gitDir := cmdr.FirstLine(output)
-	if !filepath.IsAbs(gitDir) {
-		if chdir != "" {
-			gitDir = filepath.Join(chdir, gitDir)
-		}
-
-		gitDir, err = filepath.Abs(gitDir)
-		if err != nil {
-			return "", err
-		}
-
-		gitDir = filepath.Clean(gitDir)
-	}
-
-	cachedDir = gitDir
-	return gitDir, nil
+	return dirs.GitDir, nil
 }
 
 // SetWorkdir for the std
@@ -79,47 +43,26 @@ func Workdir() (string, error) {
 	return WorkdirName()
 }
 
-// WorkdirName get git workdir name
+// WorkdirName get git workdir name. it is the top level dir of the worktree.
 func WorkdirName() (string, error) {
-	// git rev-parse --show-toplevel
-	toplevelCmd := gitCmd("rev-parse", "--show-toplevel")
-	toplevelCmd.Stderr = nil
-	output, err := toplevelCmd.Output()
+	dirs, err := gitCmd().ResolveGitDir()
+	if err != nil {
+		return "", err
+	}
 
-	dir := cmdr.FirstLine(output)
-	if dir == "" {
+	if dirs.Bare {
 		return "", fmt.Errorf("unable to determine git working directory")
 	}
-	return dir, err
+	return dirs.WorkTree, nil
 }
 
-// HasFile check
+// HasFile check the file exists in the git dir. eg: HasFile("refs", "stash")
 func HasFile(segments ...string) bool {
-	// The blessed way to resolve paths within git dir since Git 2.5.0
-	pathCmd := gitCmd("rev-parse", "-q", "--git-path", filepath.Join(segments...))
-	pathCmd.Stderr = nil
-	if output, err := pathCmd.Output(); err == nil {
-		if lines := cmdr.OutputLines(output); len(lines) == 1 {
-			if _, err := os.Stat(lines[0]); err == nil {
-				return true
-			}
-		}
-	}
-
-	// Fallback for older git versions
-	dir, err := DataDir()
+	dirs, err := gitCmd().ResolveGitDir()
 	if err != nil {
 		return false
 	}
-
-	s := []string{dir}
-	s = append(s, segments...)
-	path := filepath.Join(s...)
-	if _, err := os.Stat(path); err == nil {
-		return true
-	}
-
-	return false
+	return fsutil.PathExists(dirs.Path(segments...))
 }
 
 // Head read current branch name. return like: "refs/heads/main"
@@ -330,7 +273,7 @@ func Remotes() ([]string, error) {
 //	all: git var -l
 //	one: git var GIT_EDITOR
 func Var(name string) string {
-	val, err := New("var", name).Output()
+	val, err := gitCmd("var", name).Output()
 	if err != nil {
 		return ""
 	}
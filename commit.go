@@ -0,0 +1,410 @@
+package gitw
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/gitw/gitutil"
+	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/strutil"
+)
+
+// Person the author or committer of the commit
+type Person struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// String get like: "inhere <in.798@qq.com>"
+func (p Person) String() string {
+	return p.Name + " <" + p.Email + ">"
+}
+
+// SignStatus the GPG signature status of the commit. see the %G? of git log format
+type SignStatus string
+
+// sign status values
+const (
+	// SignUnknown not query the signature status. see CommitQuery.WithSignature()
+	SignUnknown SignStatus = ""
+	// SignGood a good (valid) signature
+	SignGood SignStatus = "G"
+	// SignBad a bad signature
+	SignBad SignStatus = "B"
+	// SignUnknownValidity a good signature with unknown validity
+	SignUnknownValidity SignStatus = "U"
+	// SignExpired a good signature that has expired
+	SignExpired SignStatus = "X"
+	// SignExpiredKey a good signature made by an expired key
+	SignExpiredKey SignStatus = "Y"
+	// SignRevokedKey a good signature made by a revoked key
+	SignRevokedKey SignStatus = "R"
+	// SignCannotCheck cannot check the signature. eg: missing key
+	SignCannotCheck SignStatus = "E"
+	// SignNone no signature
+	SignNone SignStatus = "N"
+)
+
+// IsSigned check the commit is signed
+func (s SignStatus) IsSigned() bool {
+	return s != SignUnknown && s != SignNone
+}
+
+// IsGood check the signature is good
+func (s SignStatus) IsGood() bool {
+	return s == SignGood || s == SignUnknownValidity
+}
+
+// Trailer a key-value trailer of the commit message. eg: "Signed-off-by: inhere <in.798@qq.com>"
+type Trailer = gitutil.Trailer
+
+// Commit struct of a git commit
+type Commit struct {
+	// Hash full commit ID
+	Hash string
+	// Parents the parent commit IDs
+	Parents []string
+	// Tree ID of the commit
+	Tree string
+
+	Author    Person
+	Committer Person
+
+	// Subject the first line of message
+	Subject string
+	// Body of the message, not contains the subject
+	Body string
+	// Trailers of the message. eg: Signed-off-by, Co-authored-by
+	Trailers []Trailer
+
+	// Sign the signature status. only valid on query with CommitQuery.WithSignature()
+	Sign SignStatus
+	// Signer name of the signature
+	Signer string
+	// SignKey the key used to sign the commit
+	SignKey string
+}
+
+// AbbrevID get abbrev commit ID, len is 7
+func (c *Commit) AbbrevID() string {
+	return strutil.Substr(c.Hash, 0, 7)
+}
+
+// IsMerge check the commit is merge commit
+func (c *Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// Message get full commit message
+func (c *Commit) Message() string {
+	if c.Body == "" {
+		return c.Subject
+	}
+	return c.Subject + "\n\n" + c.Body
+}
+
+// Trailer get first trailer value by key(case-insensitive). eg: "Fixes"
+func (c *Commit) Trailer(key string) string {
+	for _, t := range c.Trailers {
+		if t.IsKey(key) {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// TrailerValues get all trailer values by key(case-insensitive). eg: "Co-authored-by"
+func (c *Commit) TrailerValues(key string) []string {
+	var vs []string
+	for _, t := range c.Trailers {
+		if t.IsKey(key) {
+			vs = append(vs, t.Value)
+		}
+	}
+	return vs
+}
+
+// CoAuthors get co-authors from the Co-authored-by trailers.
+func (c *Commit) CoAuthors() []Person {
+	var ps []Person
+	for _, val := range c.TrailerValues(gitutil.TrailerCoAuthoredBy) {
+		name, email, _ := strings.Cut(val, "<")
+		ps = append(ps, Person{Name: strings.TrimSpace(name), Email: strings.TrimRight(email, "> ")})
+	}
+	return ps
+}
+
+// CommitQuery struct for query commits. see Repo.Commits()
+//
+// Usage:
+//
+//	q := gitw.NewCommitQuery().WithRange("v0.2.0", "HEAD").WithNoMerges().WithMaxCount(20)
+//	commits, err := repo.Commits(q)
+type CommitQuery struct {
+	// Revision range or a rev. eg: "v1.0.0..HEAD", "main"
+	Revision string
+	// Paths limit the commits that modify the paths
+	Paths []string
+	// Author limit the commits author, pattern is regex. eg: "inhere"
+	Author string
+	// Since show commits more recent than the date. eg: "2 weeks ago", "2024-01-02"
+	Since string
+	// Until show commits older than the date.
+	Until string
+	// Grep limit the commits message match the patterns
+	Grep []string
+	// FirstParent follow only the first parent commit on merge commit
+	FirstParent bool
+	// MaxCount limit the number of commits. 0 is not limited
+	MaxCount int
+	// NoMerges not contains merge commits
+	NoMerges bool
+	// Signature query the signature status. NOTE: it will call the gpg for verify, is slow.
+	Signature bool
+}
+
+// NewCommitQuery instance
+func NewCommitQuery() *CommitQuery {
+	return &CommitQuery{}
+}
+
+// WithRange set revision range. eg: from=v1.0.0, to=HEAD, will be "v1.0.0..HEAD"
+func (q *CommitQuery) WithRange(from, to string) *CommitQuery {
+	if from == "" {
+		q.Revision = to
+	} else {
+		q.Revision = from + ".." + to
+	}
+	return q
+}
+
+// WithRevision set a revision or range. eg: "main", "v1.0.0..HEAD"
+func (q *CommitQuery) WithRevision(rev string) *CommitQuery {
+	q.Revision = rev
+	return q
+}
+
+// WithPaths limit the commits that modify the paths
+func (q *CommitQuery) WithPaths(paths ...string) *CommitQuery {
+	q.Paths = append(q.Paths, paths...)
+	return q
+}
+
+// WithAuthor limit the commits author
+func (q *CommitQuery) WithAuthor(author string) *CommitQuery {
+	q.Author = author
+	return q
+}
+
+// WithSince show commits more recent than the date. eg: "2 weeks ago", "2024-01-02"
+func (q *CommitQuery) WithSince(date string) *CommitQuery {
+	q.Since = date
+	return q
+}
+
+// WithSinceTime show commits more recent than the time.
+func (q *CommitQuery) WithSinceTime(t time.Time) *CommitQuery {
+	return q.WithSince(t.Format(time.RFC3339))
+}
+
+// WithUntil show commits older than the date.
+func (q *CommitQuery) WithUntil(date string) *CommitQuery {
+	q.Until = date
+	return q
+}
+
+// WithUntilTime show commits older than the time.
+func (q *CommitQuery) WithUntilTime(t time.Time) *CommitQuery {
+	return q.WithUntil(t.Format(time.RFC3339))
+}
+
+// WithGrep limit the commits message match the patterns
+func (q *CommitQuery) WithGrep(patterns ...string) *CommitQuery {
+	q.Grep = append(q.Grep, patterns...)
+	return q
+}
+
+// WithFirstParent follow only the first parent commit
+func (q *CommitQuery) WithFirstParent() *CommitQuery {
+	q.FirstParent = true
+	return q
+}
+
+// WithMaxCount limit the number of commits
+func (q *CommitQuery) WithMaxCount(n int) *CommitQuery {
+	q.MaxCount = n
+	return q
+}
+
+// WithNoMerges not contains merge commits
+func (q *CommitQuery) WithNoMerges() *CommitQuery {
+	q.NoMerges = true
+	return q
+}
+
+// WithSignature query the signature status
+func (q *CommitQuery) WithSignature() *CommitQuery {
+	q.Signature = true
+	return q
+}
+
+// the pretty format for parse commit. each field is terminated by NUL,
+// so the message content can't break the parsing.
+const (
+	commitFormat = "%H%x00%P%x00%T%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI%x00%s%x00%b%x00"
+	commitFields = 11
+	// signature fields, will be added before the %s
+	commitSignFormat = "%G?%x00%GS%x00%GK%x00"
+)
+
+// Args build git log args for query commits. not contains the "log" command.
+func (q *CommitQuery) Args() []string {
+	format := commitFormat
+	if q.Signature {
+		format = strings.Replace(format, "%s%x00%b", commitSignFormat+"%s%x00%b", 1)
+	}
+
+	args := []string{"--format=" + format}
+	if q.MaxCount > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(q.MaxCount))
+	}
+	if q.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	if q.NoMerges {
+		args = append(args, "--no-merges")
+	}
+	if q.Author != "" {
+		args = append(args, "--author="+q.Author)
+	}
+	if q.Since != "" {
+		args = append(args, "--since="+q.Since)
+	}
+	if q.Until != "" {
+		args = append(args, "--until="+q.Until)
+	}
+	for _, pattern := range q.Grep {
+		args = append(args, "--grep="+pattern)
+	}
+
+	if q.Revision != "" {
+		args = append(args, "--end-of-options", q.Revision)
+	}
+	if len(q.Paths) > 0 {
+		args = append(args, "--")
+		args = append(args, q.Paths...)
+	}
+	return args
+}
+
+// fieldsNum get the number of fields for each commit record
+func (q *CommitQuery) fieldsNum() int {
+	if q.Signature {
+		return commitFields + 3
+	}
+	return commitFields
+}
+
+// parseCommit parse the fields of one commit record of the git log output. see commitFormat
+func parseCommit(ss []string, withSign bool) (*Commit, error) {
+	num := commitFields
+	if withSign {
+		num += 3
+	}
+	if len(ss) != num {
+		return nil, errorx.Rawf("invalid commit record: %q", ss)
+	}
+
+	c := &Commit{
+		// records are separated by newline
+		Hash:      strings.TrimLeft(ss[0], "\n"),
+		Parents:   strings.Fields(ss[1]),
+		Tree:      ss[2],
+		Author:    Person{Name: ss[3], Email: ss[4]},
+		Committer: Person{Name: ss[6], Email: ss[7]},
+		Body:      strings.TrimRight(ss[num-1], "\n"),
+		Subject:   ss[num-2],
+	}
+	c.Trailers = gitutil.ParseTrailers(c.Message())
+
+	var err error
+	if c.Author.When, err = time.Parse(time.RFC3339, ss[5]); err != nil {
+		return nil, errorx.Wrapf(err, "invalid author date of commit %s", c.Hash)
+	}
+	if c.Committer.When, err = time.Parse(time.RFC3339, ss[8]); err != nil {
+		return nil, errorx.Wrapf(err, "invalid committer date of commit %s", c.Hash)
+	}
+
+	if withSign {
+		c.Sign, c.Signer, c.SignKey = SignStatus(ss[9]), ss[10], ss[11]
+	}
+	return c, nil
+}
+
+// -------------------------------------------------
+// query commits
+// -------------------------------------------------
+
+// Commits query commits of the repo. q can be nil, will query all commits of HEAD.
+//
+// Usage:
+//
+//	commits, err := repo.Commits(gitw.NewCommitQuery().WithMaxCount(10))
+func (r *Repo) Commits(q *CommitQuery) ([]*Commit, error) {
+	var commits []*Commit
+	err := r.EachCommit(q, func(c *Commit) error {
+		commits = append(commits, c)
+		return nil
+	})
+	return commits, err
+}
+
+// EachCommit query commits and call fn for each commit by streaming.
+// fn can return ErrStopStream for stop.
+func (r *Repo) EachCommit(q *CommitQuery, fn func(c *Commit) error) error {
+	if q == nil {
+		q = NewCommitQuery()
+	}
+
+	num := q.fieldsNum()
+	fields := make([]string, 0, num)
+	err := r.gw.Log(q.Args()...).StreamBy(0, func(field []byte) error {
+		// the newline after the last record
+		if len(fields) == 0 && len(bytes.TrimSpace(field)) == 0 {
+			return nil
+		}
+
+		if fields = append(fields, string(field)); len(fields) < num {
+			return nil
+		}
+
+		c, err := parseCommit(fields, q.Signature)
+		if err != nil {
+			return err
+		}
+		fields = fields[:0]
+		return fn(c)
+	})
+
+	if err == nil && len(fields) > 0 {
+		err = errorx.Rawf("invalid commit record: %q", fields)
+	}
+	return err
+}
+
+// GetCommit get a commit by revision. eg: HEAD, main, v1.0.0, commit ID
+func (r *Repo) GetCommit(rev string) (*Commit, error) {
+	q := NewCommitQuery().WithRevision(rev).WithMaxCount(1)
+	commits, err := r.Commits(q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(commits) == 0 {
+		return nil, errorx.Rawf("commit not found by revision %q", rev)
+	}
+	return commits[0], nil
+}
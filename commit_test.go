@@ -0,0 +1,93 @@
+package gitw_test
+
+import (
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/gitw/gitwtest"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+// create a temp repo with some commits for tests
+func newTempRepo(t *testing.T) *gitw.Repo {
+	return gitwtest.NewTempRepo(t, "feat: first commit", "fix: second commit\n\nthe body line1\nline2\n\nFixes: #23\nSigned-off-by: inhere <in.798@qq.com>\nCo-authored-by: tom <tom@example.com>")
+}
+
+func TestRepo_Commits(t *testing.T) {
+	r := newTempRepo(t)
+
+	commits, err := r.Commits(nil)
+	assert.NoErr(t, err)
+	assert.Len(t, commits, 2)
+
+	c := commits[0]
+	assert.Len(t, c.Hash, 40)
+	assert.Eq(t, c.Hash[:7], c.AbbrevID())
+	assert.Len(t, c.Tree, 40)
+	assert.Eq(t, []string{commits[1].Hash}, c.Parents)
+	assert.False(t, c.IsMerge())
+	assert.Eq(t, "fix: second commit", c.Subject)
+	assert.Eq(t, "the body line1\nline2\n\nFixes: #23\nSigned-off-by: inhere <in.798@qq.com>\nCo-authored-by: tom <tom@example.com>", c.Body)
+	assert.Eq(t, "inhere <in.798@qq.com>", c.Author.String())
+	assert.Eq(t, int64(1704179045), c.Author.When.Unix())
+	assert.Eq(t, int64(1704265445), c.Committer.When.Unix())
+	assert.Eq(t, []gitw.Trailer{
+		{Key: "Fixes", Value: "#23"},
+		{Key: "Signed-off-by", Value: "inhere <in.798@qq.com>"},
+		{Key: "Co-authored-by", Value: "tom <tom@example.com>"},
+	}, c.Trailers)
+	assert.Eq(t, "#23", c.Trailer("fixes"))
+	assert.Eq(t, []gitw.Person{{Name: "tom", Email: "tom@example.com"}}, c.CoAuthors())
+	assert.Eq(t, gitw.SignUnknown, c.Sign)
+
+	assert.Empty(t, commits[1].Parents)
+	assert.Eq(t, "feat: first commit", commits[1].Message())
+
+	// query
+	commits, err = r.Commits(gitw.NewCommitQuery().WithGrep("^feat").WithSignature())
+	assert.NoErr(t, err)
+	assert.Len(t, commits, 1)
+	assert.Eq(t, "feat: first commit", commits[0].Subject)
+	assert.Eq(t, gitw.SignNone, commits[0].Sign)
+	assert.False(t, commits[0].Sign.IsSigned())
+
+	c, err = r.GetCommit("HEAD~1")
+	assert.NoErr(t, err)
+	assert.Eq(t, "feat: first commit", c.Subject)
+
+	_, err = r.GetCommit("not-exists")
+	assert.ErrIs(t, err, gitw.ErrUnknownRevision)
+
+	// the message contains the unit separator
+	assert.NoErr(t, r.Cmd("commit", "-q", "--allow-empty", "-m", "chore: a\x1fb\n\nbody\x1fline").Run())
+	commits, err = r.Commits(gitw.NewCommitQuery().WithMaxCount(2).WithSignature())
+	assert.NoErr(t, err)
+	assert.Len(t, commits, 2)
+	assert.Eq(t, "chore: a\x1fb", commits[0].Subject)
+	assert.Eq(t, "body\x1fline", commits[0].Body)
+	assert.Eq(t, gitw.SignNone, commits[0].Sign)
+	assert.Eq(t, "fix: second commit", commits[1].Subject)
+
+	// the revision like an option
+	_, err = r.GetCommit("--all")
+	assert.Err(t, err)
+}
+
+func TestCommitQuery_Args(t *testing.T) {
+	q := gitw.NewCommitQuery().
+		WithRange("v0.1.0", "HEAD").
+		WithPaths("repo.go").
+		WithAuthor("inhere").
+		WithSince("2 weeks ago").
+		WithGrep("fix", "feat").
+		WithFirstParent().
+		WithNoMerges().
+		WithMaxCount(10)
+
+	args := q.Args()
+	assert.StrContains(t, args[0], "--format=%H%x00")
+	assert.Eq(t, []string{
+		"--max-count=10", "--first-parent", "--no-merges", "--author=inhere", "--since=2 weeks ago",
+		"--grep=fix", "--grep=feat", "--end-of-options", "v0.1.0..HEAD", "--", "repo.go",
+	}, args[1:])
+}
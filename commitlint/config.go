@@ -0,0 +1,100 @@
+package commitlint
+
+import (
+	"strings"
+)
+
+// built in rule names
+const (
+	RuleHeaderFormat      = "header-format"
+	RuleTypeEnum          = "type-enum"
+	RuleScopeEnum         = "scope-enum"
+	RuleSubjectCase       = "subject-case"
+	RuleHeaderMaxLength   = "header-max-length"
+	RuleBodyMaxLineLength = "body-max-line-length"
+	RuleTrailerRequired   = "trailer-required"
+	RuleForbiddenWords    = "forbidden-words"
+)
+
+// level of the rule issue
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelOff     = "off"
+)
+
+// allowed subject case values
+const (
+	// SubjectCaseAny not check the subject case
+	SubjectCaseAny = ""
+	// SubjectCaseLower the subject must be all lower case.
+	SubjectCaseLower = "lower"
+	// SubjectCaseLowerFirst the first letter of subject must be lower case.
+	SubjectCaseLowerFirst = "lower-first"
+	// SubjectCaseUpperFirst the first letter of subject must be upper case. aka sentence case.
+	SubjectCaseUpperFirst = "upper-first"
+)
+
+// DefaultTypes the conventional commit types
+var DefaultTypes = []string{
+	"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert",
+}
+
+// Config struct for the commit message linter
+//
+// Example YAML:
+//
+//	types: [feat, fix, docs, chore]
+//	scopes: [api, cli]
+//	subject_case: lower-first
+//	header_max_len: 72
+//	body_max_line_len: 100
+//	required_trailers: [Signed-off-by]
+//	forbidden_words: [wip, tmp]
+//	levels:
+//	  body-max-line-length: warning
+//	  forbidden-words: off
+type Config struct {
+	// Types allowed commit types. empty for allow any type.
+	Types []string `json:"types" yaml:"types"`
+	// Scopes allowed commit scopes. empty for allow any scope.
+	Scopes []string `json:"scopes" yaml:"scopes"`
+	// SubjectCase the case of the subject(description). see SubjectCase*
+	SubjectCase string `json:"subject_case" yaml:"subject_case"`
+	// HeaderMaxLen max runes length of the header line. 0 for not limit.
+	HeaderMaxLen int `json:"header_max_len" yaml:"header_max_len"`
+	// BodyMaxLineLen max runes length of each body line. 0 for not limit.
+	BodyMaxLineLen int `json:"body_max_line_len" yaml:"body_max_line_len"`
+	// RequiredTrailers the trailer keys must be exists. eg: Signed-off-by
+	RequiredTrailers []string `json:"required_trailers" yaml:"required_trailers"`
+	// ForbiddenWords the words can not appear in the message, is case-insensitive.
+	ForbiddenWords []string `json:"forbidden_words" yaml:"forbidden_words"`
+	// Ignores the message prefixes to skip lint. eg: "Merge ", "fixup! "
+	Ignores []string `json:"ignores" yaml:"ignores"`
+	// Levels custom rule level. key is rule name, value allow: error, warning, off
+	Levels map[string]string `json:"levels" yaml:"levels"`
+}
+
+// NewDefaultConfig instance
+func NewDefaultConfig() *Config {
+	return &Config{
+		Types:          DefaultTypes,
+		HeaderMaxLen:   100,
+		BodyMaxLineLen: 100,
+		Ignores:        []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "},
+	}
+}
+
+// RuleLevel get the level of rule. default is LevelError
+func (c *Config) RuleLevel(rule string) string {
+	if lv, ok := c.Levels[rule]; ok {
+		lv = strings.ToLower(strings.TrimSpace(lv))
+		switch lv {
+		case LevelWarning, "warn":
+			return LevelWarning
+		case LevelOff, "disable", "false":
+			return LevelOff
+		}
+	}
+	return LevelError
+}
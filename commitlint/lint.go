@@ -0,0 +1,330 @@
+// Package commitlint lint the git commit message by configurable rules.
+package commitlint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/gitw/gitutil"
+	"github.com/gookit/goutil/arrutil"
+	"github.com/gookit/goutil/errorx"
+)
+
+// scissors line of the commit message, the contents below it will be ignored.
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// Issue of the lint result
+type Issue struct {
+	// Rule name. see Rule*
+	Rule string `json:"rule"`
+	// Level of the issue. see Level*
+	Level string `json:"level"`
+	// Line number, start from 1. 0 for the whole message.
+	Line int `json:"line"`
+	// Column number of the line, start from 1. count by runes
+	Column int `json:"column"`
+	// Message of the issue
+	Message string `json:"message"`
+}
+
+// Pos string. eg: "1:6"
+func (i Issue) Pos() string {
+	if i.Line == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", i.Line, i.Column)
+}
+
+// String of the issue. eg: "1:6 error scope-enum: the scope ..."
+func (i Issue) String() string {
+	return fmt.Sprintf("%s %s %s: %s", i.Pos(), i.Level, i.Rule, i.Message)
+}
+
+// Result of lint a commit message
+type Result struct {
+	// Hash of the commit, is empty on lint a message.
+	Hash string `json:"hash,omitempty"`
+	// Header line of the message
+	Header string `json:"header"`
+	// Ignored by the Config.Ignores or is a merge commit
+	Ignored bool `json:"ignored,omitempty"`
+	// Issues list
+	Issues []Issue `json:"issues"`
+}
+
+// Valid check the result has no error issues
+func (r *Result) Valid() bool { return r.ErrorCount() == 0 }
+
+// ErrorCount of the issues
+func (r *Result) ErrorCount() int { return r.count(LevelError) }
+
+// WarningCount of the issues
+func (r *Result) WarningCount() int { return r.count(LevelWarning) }
+
+func (r *Result) count(level string) (n int) {
+	for _, i := range r.Issues {
+		if i.Level == level {
+			n++
+		}
+	}
+	return
+}
+
+// Linter struct
+type Linter struct {
+	cfg *Config
+	// regex for match forbidden words
+	wordsReg *regexp.Regexp
+}
+
+// New create a linter with config. cfg can be nil, will use the default config.
+func New(cfg *Config) *Linter {
+	if cfg == nil {
+		cfg = NewDefaultConfig()
+	}
+
+	l := &Linter{cfg: cfg}
+	if len(cfg.ForbiddenWords) > 0 {
+		words := make([]string, 0, len(cfg.ForbiddenWords))
+		for _, w := range cfg.ForbiddenWords {
+			if w = strings.TrimSpace(w); w != "" {
+				words = append(words, regexp.QuoteMeta(w))
+			}
+		}
+		if len(words) > 0 {
+			l.wordsReg = regexp.MustCompile(`(?i)\b(` + strings.Join(words, "|") + `)\b`)
+		}
+	}
+	return l
+}
+
+// Config get
+func (l *Linter) Config() *Config { return l.cfg }
+
+// CleanMessage remove the comment lines and the contents below the scissors line,
+// like git does for the commit message file.
+func CleanMessage(msg string) string {
+	lines := strings.Split(strings.ReplaceAll(msg, "\r\n", "\n"), "\n")
+
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line == scissorsLine {
+			break
+		}
+		if !strings.HasPrefix(line, "#") {
+			kept = append(kept, strings.TrimRight(line, " \t"))
+		}
+	}
+	return strings.Trim(strings.Join(kept, "\n"), "\n")
+}
+
+// Lint the commit message. the message should be cleaned, see CleanMessage
+func (l *Linter) Lint(msg string) *Result {
+	header, _, _ := strings.Cut(msg, "\n")
+	res := &Result{Header: header}
+	if l.isIgnored(header) {
+		res.Ignored = true
+		return res
+	}
+
+	lt := &lintTask{Linter: l, res: res, msg: msg, header: strings.TrimRight(header, " ")}
+	lt.checkHeader()
+	lt.checkBody()
+	lt.checkTrailers()
+	lt.checkWords()
+	return res
+}
+
+func (l *Linter) isIgnored(header string) bool {
+	for _, prefix := range l.cfg.Ignores {
+		if prefix != "" && strings.HasPrefix(header, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// LintCommit lint the message of the commit. will ignore the merge commit.
+func (l *Linter) LintCommit(c *gitw.Commit) *Result {
+	if c.IsMerge() {
+		return &Result{Hash: c.Hash, Header: c.Subject, Ignored: true}
+	}
+
+	res := l.Lint(c.Message())
+	res.Hash = c.Hash
+	return res
+}
+
+// LintRevision lint the message of a commit by revision. eg: HEAD, v1.0.0, commit ID
+func (l *Linter) LintRevision(repo *gitw.Repo, rev string) (*Result, error) {
+	c, err := repo.GetCommit(rev)
+	if err != nil {
+		return nil, err
+	}
+	return l.LintCommit(c), nil
+}
+
+// LintRange lint the messages of commits in the revision range. eg: "v1.0.0..HEAD", "main..feat-x"
+func (l *Linter) LintRange(repo *gitw.Repo, revRange string) ([]*Result, error) {
+	if !strings.Contains(revRange, "..") {
+		return nil, errorx.Rawf("invalid revision range %q, must be as FROM..TO", revRange)
+	}
+
+	var results []*Result
+	err := repo.EachCommit(gitw.NewCommitQuery().WithRevision(revRange), func(c *gitw.Commit) error {
+		results = append(results, l.LintCommit(c))
+		return nil
+	})
+	return results, err
+}
+
+// lintTask for lint one message
+type lintTask struct {
+	*Linter
+	res *Result
+	msg string
+	// first line of msg
+	header string
+}
+
+func (lt *lintTask) addIssue(rule string, line, col int, format string, args ...any) {
+	level := lt.cfg.RuleLevel(rule)
+	if level == LevelOff {
+		return
+	}
+
+	lt.res.Issues = append(lt.res.Issues, Issue{
+		Rule:    rule,
+		Level:   level,
+		Line:    line,
+		Column:  col,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// column of the byte index in the header. count by runes
+func (lt *lintTask) headerCol(idx int) int {
+	if idx < 0 {
+		return 1
+	}
+	return utf8.RuneCountInString(lt.header[:idx]) + 1
+}
+
+func (lt *lintTask) checkHeader() {
+	if maxLen := lt.cfg.HeaderMaxLen; maxLen > 0 {
+		if n := utf8.RuneCountInString(lt.header); n > maxLen {
+			lt.addIssue(RuleHeaderMaxLength, 1, maxLen+1, "header length %d exceeds the max length %d", n, maxLen)
+		}
+	}
+
+	cc, err := gitutil.ParseConventional(lt.msg)
+	if err != nil {
+		if pe, ok := err.(*gitutil.ParseError); ok {
+			lt.addIssue(RuleHeaderFormat, pe.Line, pe.Column, "%s", pe.Msg)
+		} else {
+			lt.addIssue(RuleHeaderFormat, 1, 1, "%s", err.Error())
+		}
+		return
+	}
+
+	// the Header is the header line without emoji prefix
+	typeIdx := len(lt.header) - len(cc.Header)
+	if len(lt.cfg.Types) > 0 && !arrutil.StringsHas(lt.cfg.Types, cc.Type) {
+		lt.addIssue(RuleTypeEnum, 1, lt.headerCol(typeIdx), "type %q is not allowed, allowed: %s",
+			cc.Type, strings.Join(lt.cfg.Types, ", "))
+	}
+
+	if len(lt.cfg.Scopes) > 0 {
+		for _, scope := range cc.Scopes {
+			if !arrutil.StringsHas(lt.cfg.Scopes, scope) {
+				lt.addIssue(RuleScopeEnum, 1, lt.headerCol(typeIdx+len(cc.Type)+1), "scope %q is not allowed, allowed: %s",
+					scope, strings.Join(lt.cfg.Scopes, ", "))
+			}
+		}
+	}
+
+	if msg := checkCase(cc.Description, lt.cfg.SubjectCase); msg != "" {
+		colon := typeIdx + strings.IndexByte(cc.Header, ':')
+		lt.addIssue(RuleSubjectCase, 1, lt.headerCol(colon+strings.Index(lt.header[colon:], cc.Description)), "%s", msg)
+	}
+}
+
+// checkCase check the subject case. returns the error message on not match.
+func checkCase(subject, sCase string) string {
+	first, _ := utf8.DecodeRuneInString(subject)
+	switch sCase {
+	case SubjectCaseLower:
+		if strings.ToLower(subject) != subject {
+			return "subject must be lower case"
+		}
+	case SubjectCaseLowerFirst:
+		if unicode.IsUpper(first) {
+			return "subject must start with a lower case letter"
+		}
+	case SubjectCaseUpperFirst:
+		if unicode.IsLower(first) {
+			return "subject must start with an upper case letter"
+		}
+	}
+	return ""
+}
+
+func (lt *lintTask) checkBody() {
+	maxLen := lt.cfg.BodyMaxLineLen
+	if maxLen <= 0 {
+		return
+	}
+
+	// body without the trailer block. line 1 is header, line 2 is blank line.
+	body, _ := gitutil.SplitTrailers(lt.msg)
+	lines := strings.Split(body, "\n")
+	for i := 2; i < len(lines); i++ {
+		line := lines[i]
+		// allow long URL or path, it can not be wrapped.
+		if !strings.ContainsAny(strings.TrimSpace(line), " \t") {
+			continue
+		}
+
+		if n := utf8.RuneCountInString(line); n > maxLen {
+			lt.addIssue(RuleBodyMaxLineLength, i+1, maxLen+1, "body line length %d exceeds the max length %d", n, maxLen)
+		}
+	}
+}
+
+func (lt *lintTask) checkTrailers() {
+	if len(lt.cfg.RequiredTrailers) == 0 {
+		return
+	}
+
+	trailers := gitutil.ParseTrailers(lt.msg)
+	for _, key := range lt.cfg.RequiredTrailers {
+		var found bool
+		for _, t := range trailers {
+			if t.IsKey(key) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			lt.addIssue(RuleTrailerRequired, 0, 0, "missing required trailer %q", key)
+		}
+	}
+}
+
+func (lt *lintTask) checkWords() {
+	if lt.wordsReg == nil {
+		return
+	}
+
+	for i, line := range strings.Split(lt.msg, "\n") {
+		for _, loc := range lt.wordsReg.FindAllStringIndex(line, -1) {
+			col := utf8.RuneCountInString(line[:loc[0]]) + 1
+			lt.addIssue(RuleForbiddenWords, i+1, col, "forbidden word %q", line[loc[0]:loc[1]])
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package commitlint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/gookit/gitw/commitlint"
+	"github.com/gookit/gitw/gitwtest"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestCleanMessage(t *testing.T) {
+	msg := `feat: add api
+
+# Please enter the commit message for your changes.
+some body
+# ------------------------ >8 ------------------------
+diff --git a/go.mod b/go.mod
+`
+	assert.Eq(t, "feat: add api\n\nsome body", commitlint.CleanMessage(msg))
+}
+
+func TestLinter_Lint(t *testing.T) {
+	cfg := commitlint.NewDefaultConfig()
+	cfg.Scopes = []string{"api", "cli"}
+	cfg.SubjectCase = commitlint.SubjectCaseLowerFirst
+	cfg.HeaderMaxLen = 30
+	cfg.BodyMaxLineLen = 20
+	cfg.RequiredTrailers = []string{"Signed-off-by"}
+	cfg.ForbiddenWords = []string{"wip"}
+	cfg.Levels = map[string]string{commitlint.RuleBodyMaxLineLength: "warn"}
+	l := commitlint.New(cfg)
+
+	res := l.Lint("feat(api): add new api\n\nSigned-off-by: inhere")
+	assert.True(t, res.Valid())
+	assert.Empty(t, res.Issues)
+
+	res = l.Lint("✨ Feat(db): Add new api WIP and more words\n\nthe body line is too long\nhttps://github.com/gookit/gitw/issues/12")
+	assert.False(t, res.Valid())
+	assert.Eq(t, 6, res.ErrorCount())
+	assert.Eq(t, 1, res.WarningCount())
+
+	is := res.Issues
+	assert.Eq(t, commitlint.Issue{Rule: "header-max-length", Level: "error", Line: 1, Column: 31,
+		Message: "header length 42 exceeds the max length 30"}, is[0])
+	assert.Eq(t, "1:3 error type-enum", is[1].String()[:19])
+	assert.Eq(t, "scope-enum", is[2].Rule)
+	assert.Eq(t, 8, is[2].Column)
+	assert.Eq(t, "subject-case", is[3].Rule)
+	assert.Eq(t, 13, is[3].Column)
+	assert.Eq(t, "3:21", is[4].Pos())
+	assert.Eq(t, "warning", is[4].Level)
+	assert.Eq(t, "- error trailer-required: missing required trailer \"Signed-off-by\"", is[5].String())
+	assert.Eq(t, "forbidden-words", is[6].Rule)
+	assert.Eq(t, "1:25", is[6].Pos())
+
+	// invalid header
+	res = l.Lint("feat(api) add new api")
+	assert.Len(t, res.Issues, 2)
+	assert.Eq(t, "1:10 error header-format: expect ':' after the commit type or scope", res.Issues[0].String())
+
+	// ignored
+	res = l.Lint("Merge branch 'main' into dev")
+	assert.True(t, res.Ignored)
+	assert.True(t, res.Valid())
+
+	// rule off
+	cfg.Levels[commitlint.RuleTrailerRequired] = "off"
+	assert.Empty(t, l.Lint("fix: some bug").Issues)
+}
+
+func TestLinter_LintRange(t *testing.T) {
+	r := gitwtest.NewTempRepo(t, "feat: first commit")
+	run := func(args ...string) {
+		assert.NoErr(t, r.Cmd(args[0], args[1:]...).Run())
+	}
+
+	run("tag", "v0.1.0")
+	run("commit", "-q", "--allow-empty", "-m", "fix: second commit")
+	run("commit", "-q", "--allow-empty", "-m", "update some things")
+
+	l := commitlint.New(nil)
+	results, err := l.LintRange(r, "v0.1.0..HEAD")
+	assert.NoErr(t, err)
+	assert.Len(t, results, 2)
+	assert.False(t, results[0].Valid())
+	assert.True(t, results[1].Valid())
+	assert.Len(t, results[1].Hash, 40)
+
+	res, err := l.LintRevision(r, "v0.1.0")
+	assert.NoErr(t, err)
+	assert.Eq(t, "feat: first commit", res.Header)
+
+	_, err = l.LintRange(r, "HEAD")
+	assert.Err(t, err)
+
+	// report
+	rp := commitlint.NewReport(results...)
+	assert.False(t, rp.Valid())
+	assert.Eq(t, 1, rp.Errors)
+
+	buf := new(bytes.Buffer)
+	assert.NoErr(t, rp.WriteText(buf))
+	assert.StrContains(t, buf.String(), results[0].Hash[:7]+" update some things\n    1:7   error   header-format:")
+	assert.StrContains(t, buf.String(), "2 commit messages checked, 1 errors, 0 warnings")
+
+	buf.Reset()
+	assert.NoErr(t, rp.WriteJSON(buf))
+	var data map[string]any
+	assert.NoErr(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Eq(t, float64(1), data["errors"])
+}
@@ -0,0 +1,76 @@
+package commitlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Report of lint results
+type Report struct {
+	Results  []*Result `json:"results"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+}
+
+// NewReport create report from lint results
+func NewReport(results ...*Result) *Report {
+	rp := &Report{Results: results}
+	for _, res := range results {
+		rp.Errors += res.ErrorCount()
+		rp.Warnings += res.WarningCount()
+	}
+	return rp
+}
+
+// Valid check the report has no error issues
+func (rp *Report) Valid() bool { return rp.Errors == 0 }
+
+// WriteText write the human-readable report to w.
+//
+// Output eg:
+//
+//	✖ 3f2a1b0 Feat(API): Add new api
+//	    1:1  error  type-enum: type "Feat" is not allowed, allowed: feat, fix
+//
+//	1 commit messages checked, 1 errors, 0 warnings
+func (rp *Report) WriteText(w io.Writer) error {
+	for _, res := range rp.Results {
+		if res.Ignored || len(res.Issues) == 0 {
+			continue
+		}
+
+		mark := "⚠"
+		if !res.Valid() {
+			mark = "✖"
+		}
+
+		if res.Hash != "" {
+			_, _ = fmt.Fprintf(w, "%s %s %s\n", mark, abbrevHash(res.Hash), res.Header)
+		} else {
+			_, _ = fmt.Fprintf(w, "%s %s\n", mark, res.Header)
+		}
+
+		for _, i := range res.Issues {
+			_, _ = fmt.Fprintf(w, "    %-5s %-7s %s: %s\n", i.Pos(), i.Level, i.Rule, i.Message)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	_, err := fmt.Fprintf(w, "%d commit messages checked, %d errors, %d warnings\n", len(rp.Results), rp.Errors, rp.Warnings)
+	return err
+}
+
+// WriteJSON write the JSON report to w.
+func (rp *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rp)
+}
+
+func abbrevHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
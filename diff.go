@@ -0,0 +1,489 @@
+package gitw
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// DiffLineKind the kind of diff line. value is the line prefix char.
+type DiffLineKind byte
+
+// kinds of the diff line
+const (
+	DiffLineContext DiffLineKind = ' '
+	DiffLineAdded   DiffLineKind = '+'
+	DiffLineRemoved DiffLineKind = '-'
+)
+
+// DiffLine a line of the diff hunk
+type DiffLine struct {
+	Kind DiffLineKind
+	// Content of the line, not contains the prefix char
+	Content string
+	// OldLine number in the old file. 0 on the line is added
+	OldLine int
+	// NewLine number in the new file. 0 on the line is removed
+	NewLine int
+	// NoNewline the line has no newline at end of file. "\ No newline at end of file"
+	NoNewline bool
+}
+
+// String to diff line. eg: "+some content"
+func (l DiffLine) String() string {
+	return string(l.Kind) + l.Content
+}
+
+// DiffHunk a hunk of the diff file
+//
+// Header eg: "@@ -1,3 +1,4 @@ func main() {"
+type DiffHunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	// Section heading after the "@@". eg: "func main() {"
+	Section string
+	Lines   []DiffLine
+}
+
+// Header line of the hunk. eg: "@@ -1,3 +1,4 @@ func main() {"
+func (h *DiffHunk) Header() string {
+	s := "@@ -" + strconv.Itoa(h.OldStart) + "," + strconv.Itoa(h.OldLines) +
+		" +" + strconv.Itoa(h.NewStart) + "," + strconv.Itoa(h.NewLines) + " @@"
+	if h.Section != "" {
+		s += " " + h.Section
+	}
+	return s
+}
+
+// status of the diff file
+const (
+	DiffAdded    = "A"
+	DiffDeleted  = "D"
+	DiffModified = "M"
+	DiffRenamed  = "R"
+	DiffCopied   = "C"
+)
+
+// DiffFile a file of the diff output
+type DiffFile struct {
+	// OldPath is empty on file is added
+	OldPath string
+	// NewPath is empty on file is deleted
+	NewPath string
+	// OldMode, NewMode file mode. eg: 100644
+	OldMode, NewMode string
+	// OldHash, NewHash the blob object ID of the "index" line
+	OldHash, NewHash string
+	// Status of the file. see Diff* eg: DiffAdded
+	Status string
+	// Similarity index for renamed or copied file. 0-100
+	Similarity int
+	// Binary file, has no hunks
+	Binary bool
+	Hunks  []*DiffHunk
+}
+
+// Path get the file path. returns the OldPath on file is deleted
+func (f *DiffFile) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// IsNew file is added
+func (f *DiffFile) IsNew() bool { return f.Status == DiffAdded }
+
+// IsDeleted file is deleted
+func (f *DiffFile) IsDeleted() bool { return f.Status == DiffDeleted }
+
+// IsRenamed file is renamed
+func (f *DiffFile) IsRenamed() bool { return f.Status == DiffRenamed }
+
+// Stat count the added and removed lines of the file
+func (f *DiffFile) Stat() (added, removed int) {
+	for _, h := range f.Hunks {
+		for _, l := range h.Lines {
+			switch l.Kind {
+			case DiffLineAdded:
+				added++
+			case DiffLineRemoved:
+				removed++
+			}
+		}
+	}
+	return
+}
+
+// -------------------------------------------------
+// parse diff
+// -------------------------------------------------
+
+// ParseDiff parse the output of git diff or git show. should run with --no-color.
+//
+// the lines before the first "diff --git" will be ignored, eg: the commit info of git show.
+// the combined diff of merge commit("diff --cc") is not supported, will be skipped.
+func ParseDiff(out string) ([]*DiffFile, error) {
+	p := &diffParser{}
+	for i, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if err := p.parseLine(line); err != nil {
+			return nil, errorx.Rawf("diff line %d: %s", i+1, err.Error())
+		}
+	}
+	return p.files, nil
+}
+
+type diffParser struct {
+	files []*DiffFile
+	// current file, is nil on skip lines
+	file *DiffFile
+	hunk *DiffHunk
+	// remaining lines of the hunk
+	oldLeft, newLeft int
+	oldNum, newNum   int
+}
+
+func (p *diffParser) parseLine(line string) error {
+	if p.hunk != nil && (p.oldLeft > 0 || p.newLeft > 0 || strings.HasPrefix(line, "\\")) {
+		return p.parseHunkLine(line)
+	}
+	p.hunk = nil
+
+	if strings.HasPrefix(line, "diff --git ") {
+		p.file = &DiffFile{Status: DiffModified}
+		p.file.OldPath, p.file.NewPath = parseDiffGitPaths(line[len("diff --git "):])
+		p.files = append(p.files, p.file)
+		return nil
+	}
+
+	// eg: "diff --cc file", or the preamble of git show
+	if p.file == nil || strings.HasPrefix(line, "diff ") {
+		p.file = nil
+		return nil
+	}
+
+	if strings.HasPrefix(line, "@@ ") {
+		return p.startHunk(line)
+	}
+	p.parseHeaderLine(line)
+	return nil
+}
+
+// parse the extended header lines of the file
+func (p *diffParser) parseHeaderLine(line string) {
+	f := p.file
+	key, val, _ := strings.Cut(line, " ")
+	switch key {
+	case "---", "+++":
+		// git appends a "\t" after the path contains spaces. eg: "--- a/some file.txt\t"
+		val = strings.TrimSuffix(val, "\t")
+	}
+
+	switch key {
+	case "---":
+		if val == "/dev/null" {
+			f.OldPath = ""
+		} else {
+			f.OldPath = trimDiffPrefix(unquotePath(val))
+		}
+	case "+++":
+		if val == "/dev/null" {
+			f.NewPath = ""
+		} else {
+			f.NewPath = trimDiffPrefix(unquotePath(val))
+		}
+	case "old", "new", "deleted", "similarity", "rename", "copy":
+		p.parseExtHeader(line)
+	case "index":
+		// index <hash>..<hash> [<mode>]
+		hashes, mode, _ := strings.Cut(val, " ")
+		f.OldHash, f.NewHash, _ = strings.Cut(hashes, "..")
+		if mode != "" {
+			f.OldMode, f.NewMode = mode, mode
+		}
+	case "Binary":
+		f.Binary = true
+	case "GIT":
+		// "GIT binary patch", on run with --binary
+		if val == "binary patch" {
+			f.Binary = true
+		}
+	}
+}
+
+func (p *diffParser) parseExtHeader(line string) {
+	f := p.file
+	switch {
+	case strings.HasPrefix(line, "old mode "):
+		f.OldMode = line[len("old mode "):]
+	case strings.HasPrefix(line, "new mode "):
+		f.NewMode = line[len("new mode "):]
+	case strings.HasPrefix(line, "new file mode "):
+		f.Status, f.OldPath, f.NewMode = DiffAdded, "", line[len("new file mode "):]
+	case strings.HasPrefix(line, "deleted file mode "):
+		f.Status, f.NewPath, f.OldMode = DiffDeleted, "", line[len("deleted file mode "):]
+	case strings.HasPrefix(line, "similarity index "):
+		f.Similarity, _ = strconv.Atoi(strings.TrimSuffix(line[len("similarity index "):], "%"))
+	case strings.HasPrefix(line, "rename from "):
+		f.Status, f.OldPath = DiffRenamed, unquotePath(line[len("rename from "):])
+	case strings.HasPrefix(line, "rename to "):
+		f.NewPath = unquotePath(line[len("rename to "):])
+	case strings.HasPrefix(line, "copy from "):
+		f.Status, f.OldPath = DiffCopied, unquotePath(line[len("copy from "):])
+	case strings.HasPrefix(line, "copy to "):
+		f.NewPath = unquotePath(line[len("copy to "):])
+	}
+}
+
+// start hunk by header line. eg: "@@ -1,3 +1,4 @@ func main() {"
+func (p *diffParser) startHunk(line string) error {
+	rs, section, ok := strings.Cut(line[3:], " @@")
+	if !ok {
+		return errorx.Rawf("invalid hunk header %q", line)
+	}
+
+	oldRange, newRange, ok := strings.Cut(rs, " ")
+	if !ok || !strings.HasPrefix(oldRange, "-") || !strings.HasPrefix(newRange, "+") {
+		return errorx.Rawf("invalid hunk header %q", line)
+	}
+
+	h := &DiffHunk{Section: strings.TrimPrefix(section, " ")}
+	var err1, err2 error
+	h.OldStart, h.OldLines, err1 = parseHunkRange(oldRange[1:])
+	h.NewStart, h.NewLines, err2 = parseHunkRange(newRange[1:])
+	if err1 != nil || err2 != nil {
+		return errorx.Rawf("invalid hunk header %q", line)
+	}
+
+	p.file.Hunks = append(p.file.Hunks, h)
+	p.hunk = h
+	p.oldLeft, p.newLeft = h.OldLines, h.NewLines
+	p.oldNum, p.newNum = h.OldStart, h.NewStart
+	return nil
+}
+
+// parse hunk range. eg: "1,3", "1" (the lines is 1)
+func parseHunkRange(s string) (start, lines int, err error) {
+	startStr, linesStr, ok := strings.Cut(s, ",")
+	if start, err = strconv.Atoi(startStr); err != nil {
+		return
+	}
+
+	if !ok {
+		return start, 1, nil
+	}
+	lines, err = strconv.Atoi(linesStr)
+	return
+}
+
+func (p *diffParser) parseHunkLine(line string) error {
+	h := p.hunk
+	if strings.HasPrefix(line, "\\") {
+		// "\ No newline at end of file", mark the prev line
+		if n := len(h.Lines); n > 0 {
+			h.Lines[n-1].NoNewline = true
+		}
+		return nil
+	}
+
+	// the empty context line maybe trimmed by some tools
+	kind := DiffLineContext
+	if line != "" {
+		kind = DiffLineKind(line[0])
+		line = line[1:]
+	}
+
+	dl := DiffLine{Kind: kind, Content: line}
+	switch kind {
+	case DiffLineContext:
+		dl.OldLine, dl.NewLine = p.oldNum, p.newNum
+		p.oldNum++
+		p.newNum++
+		p.oldLeft--
+		p.newLeft--
+	case DiffLineAdded:
+		dl.NewLine = p.newNum
+		p.newNum++
+		p.newLeft--
+	case DiffLineRemoved:
+		dl.OldLine = p.oldNum
+		p.oldNum++
+		p.oldLeft--
+	default:
+		return errorx.Rawf("invalid hunk line %q", string(kind)+line)
+	}
+
+	if p.oldLeft < 0 || p.newLeft < 0 {
+		return errorx.Rawf("hunk lines exceed the header %q", h.Header())
+	}
+	h.Lines = append(h.Lines, dl)
+	return nil
+}
+
+// parse paths from "a/old b/new". the paths maybe quoted on contains special chars.
+func parseDiffGitPaths(s string) (oldPath, newPath string) {
+	if strings.HasPrefix(s, `"`) {
+		if end := quotedEnd(s); end > 0 {
+			oldPath, newPath = unquotePath(s[:end]), unquotePath(strings.TrimPrefix(s[end:], " "))
+			return trimDiffPrefix(oldPath), trimDiffPrefix(newPath)
+		}
+	}
+
+	if strings.HasSuffix(s, `"`) {
+		if idx := strings.Index(s, ` "`); idx > 0 {
+			return trimDiffPrefix(s[:idx]), trimDiffPrefix(unquotePath(s[idx+1:]))
+		}
+	}
+
+	// no rename, the paths are same: "a/some file b/some file"
+	if n := len(s); n%2 == 1 && s[n/2] == ' ' && s[2:n/2] == s[n/2+3:] {
+		return trimDiffPrefix(s[:n/2]), trimDiffPrefix(s[n/2+1:])
+	}
+
+	// the "rename from/to" or "---/+++" lines will fix them.
+	oldPath, newPath, _ = strings.Cut(s, " ")
+	return trimDiffPrefix(oldPath), trimDiffPrefix(newPath)
+}
+
+// get the end index(exclusive) of the quoted string at start of s
+func quotedEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// unquote the path quoted by git. eg: "a/\303\244.txt"
+func unquotePath(s string) string {
+	if len(s) > 1 && s[0] == '"' && s[len(s)-1] == '"' {
+		if uq, err := strconv.Unquote(s); err == nil {
+			return uq
+		}
+	}
+	return s
+}
+
+// remove the "a/", "b/" prefix of the path
+func trimDiffPrefix(s string) string {
+	if len(s) > 2 && (s[0] == 'a' || s[0] == 'b') && s[1] == '/' {
+		return s[2:]
+	}
+	return s
+}
+
+// -------------------------------------------------
+// diff stat
+// -------------------------------------------------
+
+// DiffStat the insertions and deletions of a file. by git diff --numstat
+type DiffStat struct {
+	// OldPath is not empty on file is renamed or copied
+	OldPath string
+	Path    string
+	Added   int
+	Deleted int
+	// Binary file, Added and Deleted are 0
+	Binary bool
+}
+
+// ParseNumstat parse the output of: git diff --numstat -z
+func ParseNumstat(out string) ([]*DiffStat, error) {
+	var stats []*DiffStat
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	for i := 0; i < len(fields); i++ {
+		if strings.TrimSpace(fields[i]) == "" {
+			continue
+		}
+
+		// added \t deleted \t path
+		ss := strings.SplitN(strings.TrimLeft(fields[i], "\n"), "\t", 3)
+		if len(ss) != 3 {
+			return nil, errorx.Rawf("invalid numstat line %q", fields[i])
+		}
+
+		st := &DiffStat{Path: ss[2]}
+		if ss[0] == "-" && ss[1] == "-" {
+			st.Binary = true
+		} else {
+			var err1, err2 error
+			st.Added, err1 = strconv.Atoi(ss[0])
+			st.Deleted, err2 = strconv.Atoi(ss[1])
+			if err1 != nil || err2 != nil {
+				return nil, errorx.Rawf("invalid numstat line %q", fields[i])
+			}
+		}
+
+		// renamed or copied: the path is empty, next two fields are old and new path
+		if st.Path == "" {
+			if i+2 >= len(fields) {
+				return nil, errorx.Rawf("invalid numstat rename line %q", fields[i])
+			}
+			st.OldPath, st.Path = fields[i+1], fields[i+2]
+			i += 2
+		}
+		stats = append(stats, st)
+	}
+	return stats, nil
+}
+
+// -------------------------------------------------
+// repo diff
+// -------------------------------------------------
+
+// build diff args for compare from to. see Repo.Diff
+func diffArgs(from, to string, paths []string, extra ...string) []string {
+	args := append([]string{"--no-color", "--no-ext-diff", "-M"}, extra...)
+	if from != "" {
+		args = append(args, from)
+	}
+	if to != "" {
+		args = append(args, to)
+	}
+
+	args = append(args, "--")
+	return append(args, paths...)
+}
+
+// Diff get the parsed diff files between from and to. can limit by paths.
+//
+//   - from and to are empty: the worktree changes not staged
+//   - to is empty: compare from with the worktree. eg: "HEAD", "--cached"
+//
+// Usage:
+//
+//	files, err := repo.Diff("v1.0.0", "HEAD")
+//	files, err := repo.Diff("HEAD~1", "HEAD", "README.md")
+func (r *Repo) Diff(from, to string, paths ...string) ([]*DiffFile, error) {
+	args := diffArgs(from, to, paths, "--src-prefix=a/", "--dst-prefix=b/")
+	out, err := r.gw.Diff(args...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiff(out)
+}
+
+// CommitDiff get the parsed diff files of a commit. for merge commit, compare with the first parent.
+func (r *Repo) CommitDiff(rev string, paths ...string) ([]*DiffFile, error) {
+	args := []string{"--format=", "--no-color", "--no-ext-diff", "-M", "--first-parent",
+		"--src-prefix=a/", "--dst-prefix=b/", rev, "--"}
+	out, err := r.gw.Show(append(args, paths...)...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiff(out)
+}
+
+// DiffStat get the insertions and deletions of each file between from and to. see Repo.Diff
+func (r *Repo) DiffStat(from, to string, paths ...string) ([]*DiffStat, error) {
+	out, err := r.gw.Diff(diffArgs(from, to, paths, "--numstat", "-z")...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseNumstat(out)
+}
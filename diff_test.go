@@ -0,0 +1,162 @@
+package gitw_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+var testDiffOut = `commit 0b8a2a1c9e1f7e9d5d6c4e7b2f7f7a0f1c2b3d4e
+Author: inhere <in.798@qq.com>
+
+    feat: some message
+
+diff --git a/README.md b/README.md
+index 3b18e51..a042389 100644
+--- a/README.md
++++ b/README.md
+@@ -1,4 +1,5 @@ # Gitw
+ line1
+-line2
++line2 changed
++line3 added
+
+ line4
+\ No newline at end of file
+diff --git a/old name.txt b/new name.txt
+similarity index 90%
+rename from old name.txt
+rename to new name.txt
+index 1111111..2222222
+--- a/old name.txt
++++ b/new name.txt
+@@ -3 +3 @@
+-old
++new
+diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..3333333
+Binary files /dev/null and b/logo.png differ
+diff --git "a/\303\244.txt" "b/\303\244.txt"
+deleted file mode 100644
+index 4444444..0000000
+--- "a/\303\244.txt"
++++ /dev/null
+@@ -1 +0,0 @@
+-content
+`
+
+func TestParseDiff(t *testing.T) {
+	files, err := gitw.ParseDiff(testDiffOut)
+	assert.NoErr(t, err)
+	assert.Len(t, files, 5)
+
+	f := files[0]
+	assert.Eq(t, "README.md", f.Path())
+	assert.Eq(t, gitw.DiffModified, f.Status)
+	assert.Eq(t, "3b18e51", f.OldHash)
+	assert.Eq(t, "100644", f.NewMode)
+	assert.Len(t, f.Hunks, 1)
+
+	h := f.Hunks[0]
+	assert.Eq(t, "@@ -1,4 +1,5 @@ # Gitw", h.Header())
+	assert.Len(t, h.Lines, 6)
+	assert.Eq(t, gitw.DiffLine{Kind: gitw.DiffLineRemoved, Content: "line2", OldLine: 2}, h.Lines[1])
+	assert.Eq(t, "+line3 added", h.Lines[3].String())
+	assert.Eq(t, 3, h.Lines[3].NewLine)
+	// empty context line
+	assert.Eq(t, gitw.DiffLine{Kind: gitw.DiffLineContext, OldLine: 3, NewLine: 4}, h.Lines[4])
+	assert.True(t, h.Lines[5].NoNewline)
+	added, removed := f.Stat()
+	assert.Eq(t, 2, added)
+	assert.Eq(t, 1, removed)
+
+	f = files[1]
+	assert.True(t, f.IsRenamed())
+	assert.Eq(t, "old name.txt", f.OldPath)
+	assert.Eq(t, "new name.txt", f.NewPath)
+	assert.Eq(t, 90, f.Similarity)
+	assert.Eq(t, 3, f.Hunks[0].OldStart)
+	assert.Eq(t, 1, f.Hunks[0].OldLines)
+
+	f = files[2]
+	assert.Eq(t, "run.sh", f.Path())
+	assert.Eq(t, "100644", f.OldMode)
+	assert.Eq(t, "100755", f.NewMode)
+	assert.Empty(t, f.Hunks)
+
+	f = files[3]
+	assert.True(t, f.IsNew())
+	assert.True(t, f.Binary)
+	assert.Eq(t, "", f.OldPath)
+	assert.Eq(t, "logo.png", f.Path())
+
+	f = files[4]
+	assert.True(t, f.IsDeleted())
+	assert.Eq(t, "ä.txt", f.Path())
+	assert.Eq(t, "", f.NewPath)
+
+	// invalid
+	_, err = gitw.ParseDiff("diff --git a/a b/a\n@@ -1,x +1 @@\n")
+	assert.ErrMsg(t, err, `diff line 2: invalid hunk header "@@ -1,x +1 @@"`)
+}
+
+func TestParseNumstat(t *testing.T) {
+	stats, err := gitw.ParseNumstat("3\t1\tREADME.md\x00-\t-\tlogo.png\x001\t1\t\x00old.txt\x00new.txt\x00")
+	assert.NoErr(t, err)
+	assert.Len(t, stats, 3)
+	assert.Eq(t, gitw.DiffStat{Path: "README.md", Added: 3, Deleted: 1}, *stats[0])
+	assert.True(t, stats[1].Binary)
+	assert.Eq(t, "old.txt", stats[2].OldPath)
+	assert.Eq(t, "new.txt", stats[2].Path)
+
+	_, err = gitw.ParseNumstat("3\tREADME.md\x00")
+	assert.Err(t, err)
+}
+
+func TestRepo_Diff(t *testing.T) {
+	r := newTempRepo(t)
+	file := filepath.Join(r.Dir(), "hello.txt")
+	assert.NoErr(t, os.WriteFile(file, []byte("hello\nworld\n"), 0644))
+	assert.NoErr(t, r.Cmd("add", "hello.txt").Run())
+	assert.NoErr(t, r.Cmd("commit", "-q", "-m", "feat: add hello").Run())
+	assert.NoErr(t, os.WriteFile(file, []byte("hello\ngitw\nworld\n"), 0644))
+
+	// worktree changes
+	files, err := r.Diff("", "")
+	assert.NoErr(t, err)
+	assert.Len(t, files, 1)
+	assert.Eq(t, "hello.txt", files[0].Path())
+	assert.Eq(t, "+gitw", files[0].Hunks[0].Lines[1].String())
+
+	files, err = r.Diff("HEAD~1", "HEAD")
+	assert.NoErr(t, err)
+	assert.Len(t, files, 1)
+	assert.True(t, files[0].IsNew())
+
+	files, err = r.CommitDiff("HEAD")
+	assert.NoErr(t, err)
+	assert.Len(t, files, 1)
+	assert.Eq(t, 2, files[0].Hunks[0].NewLines)
+
+	stats, err := r.DiffStat("HEAD", "", "hello.txt")
+	assert.NoErr(t, err)
+	assert.Len(t, stats, 1)
+	assert.Eq(t, 1, stats[0].Added)
+	assert.Eq(t, 0, stats[0].Deleted)
+
+	// path contains spaces
+	assert.NoErr(t, os.WriteFile(filepath.Join(r.Dir(), "some file.txt"), []byte("hi\n"), 0644))
+	assert.NoErr(t, r.Cmd("add", "some file.txt").Run())
+	files, err = r.Diff("HEAD", "")
+	assert.NoErr(t, err)
+	assert.Len(t, files, 2)
+	assert.Eq(t, "some file.txt", files[1].NewPath)
+	assert.Eq(t, "hello.txt", files[0].OldPath)
+}
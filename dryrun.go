@@ -0,0 +1,273 @@
+package gitw
+
+import (
+	"strings"
+	"sync"
+)
+
+// PlannedCmd a mutating command recorded on dry run.
+type PlannedCmd struct {
+	// Cmdline the command line
+	Cmdline string
+	// Workdir for run git
+	Workdir string
+}
+
+// String to string
+func (pc PlannedCmd) String() string {
+	if pc.Workdir == "" {
+		return pc.Cmdline
+	}
+	return "(" + pc.Workdir + ") " + pc.Cmdline
+}
+
+// DryRunPlan the mutating commands recorded on dry run. It is safe for concurrent use.
+type DryRunPlan struct {
+	mu   sync.Mutex
+	cmds []PlannedCmd
+}
+
+// Add a planned command
+func (p *DryRunPlan) Add(gw *GitWrap) {
+	p.mu.Lock()
+	p.cmds = append(p.cmds, PlannedCmd{Cmdline: gw.Cmdline(), Workdir: gw.Workdir})
+	p.mu.Unlock()
+}
+
+// Cmds get planned commands
+func (p *DryRunPlan) Cmds() []PlannedCmd {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PlannedCmd(nil), p.cmds...)
+}
+
+// Cmdlines get planned command lines
+func (p *DryRunPlan) Cmdlines() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ss := make([]string, len(p.cmds))
+	for i, pc := range p.cmds {
+		ss[i] = pc.Cmdline
+	}
+	return ss
+}
+
+// Len of the planned commands
+func (p *DryRunPlan) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.cmds)
+}
+
+// Reset clear the planned commands
+func (p *DryRunPlan) Reset() {
+	p.mu.Lock()
+	p.cmds = nil
+	p.mu.Unlock()
+}
+
+// String get planned commands, one command per line.
+func (p *DryRunPlan) String() string {
+	var sb strings.Builder
+	for _, pc := range p.Cmds() {
+		sb.WriteString(pc.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// read-only git commands, can be executed on dry run.
+var readOnlyCmds = map[string]bool{
+	"annotate":      true,
+	"blame":         true,
+	"cat-file":      true,
+	"check-attr":    true,
+	"check-ignore":  true,
+	"cherry":        true,
+	"count-objects": true,
+	"describe":      true,
+	"diff":          true,
+	"diff-files":    true,
+	"diff-index":    true,
+	"diff-tree":     true,
+	"for-each-ref":  true,
+	"grep":          true,
+	"help":          true,
+	"log":           true,
+	"ls-files":      true,
+	"ls-remote":     true,
+	"ls-tree":       true,
+	"merge-base":    true,
+	"name-rev":      true,
+	"rev-list":      true,
+	"rev-parse":     true,
+	"shortlog":      true,
+	"show":          true,
+	"show-ref":      true,
+	"status":        true,
+	"var":           true,
+	"verify-commit": true,
+	"verify-tag":    true,
+	"version":       true,
+	"whatchanged":   true,
+}
+
+// read-only sub actions for the git commands. eg: `git stash list`
+var readOnlyActions = map[string][]string{
+	"notes":     {"list", "show"},
+	"reflog":    {"show", "exists"},
+	"remote":    {"-v", "--verbose", "show", "get-url"},
+	"stash":     {"list", "show"},
+	"submodule": {"status", "summary"},
+	"worktree":  {"list"},
+}
+
+// global flags of git, the next arg is the flag value.
+var flagsWithValue = map[string]bool{
+	"-c":           true,
+	"-C":           true,
+	"--git-dir":    true,
+	"--work-tree":  true,
+	"--namespace":  true,
+	"--exec-path":  true,
+	"--config-env": true,
+}
+
+// IsReadOnlyCmd check the git command args is read-only, args can contain global flags.
+//
+// Usage:
+//
+//	IsReadOnlyCmd([]string{"branch", "-v", "--all"}) // true
+//	IsReadOnlyCmd([]string{"branch", "-D", "fea_x"}) // false
+func IsReadOnlyCmd(args []string) bool {
+	// skip global flags
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if flagsWithValue[args[0]] {
+			args = args[1:]
+		}
+		if len(args) > 0 {
+			args = args[1:]
+		}
+	}
+
+	if len(args) == 0 {
+		return true // eg: git --version
+	}
+
+	cmd, args := args[0], args[1:]
+	if readOnlyCmds[cmd] {
+		return true
+	}
+
+	if actions, ok := readOnlyActions[cmd]; ok {
+		if len(args) == 0 {
+			// `git stash` will push; `git remote`, `git reflog` ... will list
+			return cmd != "stash" && cmd != "submodule"
+		}
+
+		for _, action := range actions {
+			if args[0] == action {
+				return true
+			}
+		}
+		// reflog: `git reflog REF` is show
+		return cmd == "reflog" && args[0] != "expire" && args[0] != "delete"
+	}
+
+	switch cmd {
+	case "branch":
+		return isReadOnlyRefCmd(args, branchListFlags, branchEditFlags)
+	case "tag":
+		return isReadOnlyRefCmd(args, tagListFlags, tagEditFlags)
+	case "config":
+		return isReadOnlyConfig(args)
+	case "symbolic-ref":
+		flags, pos := splitFlagArgs(args)
+		return len(pos) <= 1 && !hasOneFlag(flags, []string{"-d", "--delete"})
+	}
+	return false
+}
+
+var (
+	branchListFlags = []string{
+		"-l", "--list", "-a", "--all", "-r", "--remotes", "--show-current", "--contains", "--no-contains",
+		"--merged", "--no-merged", "--points-at", "--format", "--sort", "-v", "-vv", "--verbose",
+	}
+	branchEditFlags = []string{
+		"-d", "-D", "--delete", "-m", "-M", "--move", "-c", "-C", "--copy", "-u", "--set-upstream-to",
+		"--unset-upstream", "--edit-description", "-f", "--force", "-t", "--track", "--no-track",
+	}
+	tagListFlags = []string{
+		"-l", "--list", "-n", "--contains", "--no-contains", "--merged", "--no-merged", "--points-at",
+		"--format", "--sort", "-v", "--verify",
+	}
+	tagEditFlags = []string{
+		"-d", "--delete", "-a", "--annotate", "-s", "--sign", "-u", "--local-user", "-f", "--force",
+		"-m", "--message", "-F", "--file",
+	}
+)
+
+// for branch, tag command check
+func isReadOnlyRefCmd(args, listFlags, editFlags []string) bool {
+	flags, pos := splitFlagArgs(args)
+	if hasOneFlag(flags, editFlags) {
+		return false
+	}
+	// eg: `git branch NAME` will create branch
+	return len(pos) == 0 || hasOneFlag(flags, listFlags)
+}
+
+func isReadOnlyConfig(args []string) bool {
+	flags, pos := splitFlagArgs(args)
+	if hasOneFlag(flags, []string{
+		"--get", "--get-all", "--get-regexp", "--get-urlmatch", "--get-color", "--get-colorbool", "-l", "--list",
+	}) {
+		return true
+	}
+
+	if hasOneFlag(flags, []string{
+		"--add", "--unset", "--unset-all", "--replace-all", "--rename-section", "--remove-section", "-e", "--edit",
+	}) {
+		return false
+	}
+
+	// new style: git config get|list NAME
+	if len(pos) > 0 && (pos[0] == "get" || pos[0] == "list") {
+		return true
+	}
+	// `git config NAME` is get; `git config NAME VALUE` is set
+	return len(pos) == 1 && pos[0] != "set" && pos[0] != "unset" && pos[0] != "edit"
+}
+
+// split args to flags and positional args. the flag value with "=" is trimmed. eg: --sort=xx => --sort
+func splitFlagArgs(args []string) (flags, pos []string) {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if strings.HasPrefix(arg, "-") {
+			name, _, _ := strings.Cut(arg, "=")
+			// eg: -n5 for git tag
+			if len(name) > 2 && name[1] != '-' && name[1] == 'n' {
+				name = "-n"
+			}
+			flags = append(flags, name)
+		} else {
+			pos = append(pos, arg)
+		}
+	}
+	return
+}
+
+func hasOneFlag(flags, check []string) bool {
+	for _, flag := range flags {
+		for _, name := range check {
+			if flag == name {
+				return true
+			}
+		}
+	}
+	return false
+}
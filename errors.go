@@ -0,0 +1,273 @@
+package gitw
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// ErrTimeout error for run git command timeout.
+//
+// Usage:
+//
+//	errors.Is(err, gitw.ErrTimeout)
+var ErrTimeout = errorx.Raw("git command execution timeout")
+
+// TimeoutError struct. returned on the git command is killed by timeout or context deadline.
+type TimeoutError struct {
+	// Cmdline the executed command line
+	Cmdline string
+	// Limit the timeout setting. 0 if the deadline comes from context.
+	Limit time.Duration
+	// Err the raw error from exec.Cmd
+	Err error
+}
+
+// Error string
+func (e *TimeoutError) Error() string {
+	if e.Limit > 0 {
+		return fmt.Sprintf("git command timeout after %s: %s", e.Limit, e.Cmdline)
+	}
+	return "git command timeout: " + e.Cmdline
+}
+
+// Unwrap get the context error and the raw error.
+// so errors.Is(err, context.DeadlineExceeded) and errors.As(err, &exitErr) both work.
+func (e *TimeoutError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{context.DeadlineExceeded}
+	}
+	return []error{context.DeadlineExceeded, e.Err}
+}
+
+// Is check the error is ErrTimeout
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// check the context state, convert the exec error to timeout or canceled error.
+func (gw *GitWrap) ctxError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return &TimeoutError{Cmdline: gw.Cmdline(), Limit: gw.Timeout, Err: err}
+	case context.Canceled:
+		return errorx.Wrap(context.Canceled, "git command canceled: "+gw.Cmdline())
+	}
+	return err
+}
+
+// ErrKind type of the git command error
+type ErrKind uint8
+
+// String get kind name
+func (k ErrKind) String() string {
+	if int(k) < len(errKindNames) {
+		return errKindNames[k]
+	}
+	return "unknown"
+}
+
+// kinds of the git command error. see ClassifyError()
+const (
+	ErrKindUnknown ErrKind = iota
+	ErrKindNotRepo
+	ErrKindUnknownRevision
+	ErrKindMergeConflict
+	ErrKindAuthFailed
+	ErrKindLockExists
+	ErrKindNetwork
+	ErrKindTimeout
+)
+
+var errKindNames = []string{"unknown", "not-repo", "unknown-revision", "merge-conflict", "auth-failed", "lock-exists", "network", "timeout"}
+
+// sentinel errors for check GitError kind.
+//
+// Usage:
+//
+//	errors.Is(err, gitw.ErrNotRepo)
+var (
+	ErrNotRepo         = errorx.Raw("not a git repository")
+	ErrUnknownRevision = errorx.Raw("unknown git revision or path")
+	ErrMergeConflict   = errorx.Raw("git merge conflict")
+	ErrAuthFailed      = errorx.Raw("git authentication failed")
+	ErrLockExists      = errorx.Raw("git lock file exists")
+	ErrNetwork         = errorx.Raw("git network error")
+)
+
+var kindErrors = map[ErrKind]error{
+	ErrKindNotRepo:         ErrNotRepo,
+	ErrKindUnknownRevision: ErrUnknownRevision,
+	ErrKindMergeConflict:   ErrMergeConflict,
+	ErrKindAuthFailed:      ErrAuthFailed,
+	ErrKindLockExists:      ErrLockExists,
+	ErrKindNetwork:         ErrNetwork,
+	ErrKindTimeout:         ErrTimeout,
+}
+
+// GitError struct. returned on run git command failed.
+//
+// Usage:
+//
+//	var ge *gitw.GitError
+//	if errors.As(err, &ge) {
+//		fmt.Println(ge.ExitCode, ge.Stderr)
+//	}
+type GitError struct {
+	// Cmdline the executed command line
+	Cmdline string
+	// Workdir for run git
+	Workdir string
+	// ExitCode of the git process. -1 on the process not started or killed.
+	ExitCode int
+	// Stderr captured stderr output
+	Stderr string
+	// Kind classified error kind by stderr
+	Kind ErrKind
+	// Err the raw error. eg: *exec.ExitError, *TimeoutError
+	Err error
+}
+
+// Error string
+func (e *GitError) Error() string {
+	msg := e.Message()
+	if msg == "" {
+		msg = e.Err.Error()
+	}
+	return fmt.Sprintf("run %q failed: %s", e.Cmdline, msg)
+}
+
+// Message get the main error message from stderr. eg: "fatal: not a git repository"
+func (e *GitError) Message() string {
+	var first string
+	for _, line := range strings.Split(e.Stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "fatal:") || strings.HasPrefix(line, "error:") {
+			return line
+		}
+		if first == "" {
+			first = line
+		}
+	}
+	return first
+}
+
+// Unwrap get raw error
+func (e *GitError) Unwrap() error {
+	return e.Err
+}
+
+// Is check the error kind by sentinel errors. eg: ErrNotRepo
+func (e *GitError) Is(target error) bool {
+	if err, ok := kindErrors[e.Kind]; ok {
+		return err == target
+	}
+	return false
+}
+
+// newGitError create GitError from exec error and captured stderr.
+func (gw *GitWrap) newGitError(err error, exitCode int, stderr string) error {
+	if err == nil {
+		return nil
+	}
+
+	ge := &GitError{
+		Cmdline:  gw.Cmdline(),
+		Workdir:  gw.Workdir,
+		ExitCode: exitCode,
+		Stderr:   stderr,
+		Err:      err,
+	}
+
+	if errors.Is(err, ErrTimeout) {
+		ge.Kind = ErrKindTimeout
+	} else {
+		ge.Kind = ClassifyError(stderr)
+	}
+	return ge
+}
+
+// error keywords for classify git error. NOTE: keywords is lower case.
+var errKindKeywords = []struct {
+	kind ErrKind
+	keys []string
+}{
+	{ErrKindNotRepo, []string{"not a git repository"}},
+	{ErrKindLockExists, []string{".lock': file exists", ".lock file exists", "another git process seems to be running"}},
+	{ErrKindMergeConflict, []string{
+		"conflict (", "merge conflict", "fix conflicts", "needs merge",
+		"you have unmerged paths", "unmerged files",
+	}},
+	{ErrKindUnknownRevision, []string{
+		"unknown revision", "bad revision", "ambiguous argument", "needed a single revision",
+		"not a valid object name", "invalid object name", "bad object", "not a valid ref",
+		"pathspec", "no such ref", "couldn't find remote ref",
+	}},
+	{ErrKindAuthFailed, []string{
+		"authentication failed", "permission denied (publickey", "could not read username",
+		"could not read password", "terminal prompts disabled", "invalid username or password",
+		"host key verification failed", "the requested url returned error: 403",
+		"the requested url returned error: 401",
+	}},
+	{ErrKindNetwork, []string{
+		"could not resolve host", "connection refused", "connection timed out", "operation timed out",
+		"network is unreachable", "failed to connect", "unable to access", "early eof",
+		"the remote end hung up unexpectedly", "could not read from remote repository",
+		"connection reset", "ssl_error", "gnutls",
+	}},
+}
+
+// ClassifyError get error kind by the stderr output of git command.
+func ClassifyError(stderr string) ErrKind {
+	if stderr == "" {
+		return ErrKindUnknown
+	}
+
+	str := strings.ToLower(stderr)
+	for _, item := range errKindKeywords {
+		for _, key := range item.keys {
+			if strings.Contains(str, key) {
+				return item.kind
+			}
+		}
+	}
+	return ErrKindUnknown
+}
+
+// maxStderrSize max captured stderr size for GitError
+const maxStderrSize = 64 * 1024
+
+// stderrBuffer capture the stderr output, only keep first maxStderrSize bytes.
+type stderrBuffer struct {
+	buf bytes.Buffer
+}
+
+// Write implements io.Writer
+func (b *stderrBuffer) Write(p []byte) (int, error) {
+	if remain := maxStderrSize - b.buf.Len(); remain > 0 {
+		if len(p) > remain {
+			b.buf.Write(p[:remain])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// String get captured
+func (b *stderrBuffer) String() string {
+	return b.buf.String()
+}
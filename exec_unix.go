@@ -0,0 +1,74 @@
+//go:build !windows
+
+package gitw
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/gookit/goutil/sysutil"
+)
+
+// setProcGroup run the command in a new process group,
+// so that can kill the git process tree on context canceled.
+//
+// If the c.Stdin is a terminal and current process is in the foreground, the new group will be
+// the foreground group of the terminal, so git can read the input and open the editor.
+// returns the func for give back the terminal after the command exited, it is nil on not need.
+//
+// NOTE: git read the credentials from /dev/tty, should set the Stdin to os.Stdin for prompt it.
+func setProcGroup(c *exec.Cmd) (restore func()) {
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	c.Cancel = func() error {
+		// negative pid: kill all processes in the group
+		return syscall.Kill(-c.Process.Pid, syscall.SIGKILL)
+	}
+
+	f, ok := c.Stdin.(*os.File)
+	if !ok || !sysutil.IsTerminal(f.Fd()) {
+		return nil
+	}
+
+	fd := int(f.Fd())
+	pgrp := syscall.Getpgrp()
+	if fg, err := tcGetpgrp(fd); err != nil || fg != pgrp {
+		return nil
+	}
+
+	c.SysProcAttr.Foreground = true
+	c.SysProcAttr.Ctty = fd
+	return func() { _ = tcSetpgrp(fd, pgrp) }
+}
+
+func tcGetpgrp(fd int) (pgrp int, err error) {
+	var pid int32
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TIOCGPGRP), uintptr(unsafe.Pointer(&pid)))
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(pid), nil
+}
+
+var ttouMu sync.Mutex
+
+// set the foreground group of the terminal. on a background process, need ignore the SIGTTOU for it.
+func tcSetpgrp(fd, pgrp int) error {
+	ttouMu.Lock()
+	defer ttouMu.Unlock()
+
+	if !signal.Ignored(syscall.SIGTTOU) {
+		signal.Ignore(syscall.SIGTTOU)
+		defer signal.Reset(syscall.SIGTTOU)
+	}
+
+	pid := int32(pgrp)
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TIOCSPGRP), uintptr(unsafe.Pointer(&pid)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
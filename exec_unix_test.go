@@ -0,0 +1,50 @@
+//go:build !windows
+
+package gitw_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestGitWrap_WithTimeout_killTree(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	gw := gitw.New("slow").
+		WithConfigOverride("alias.slow", "!echo $$ > "+pidFile+"; exec sleep 30").
+		WithTimeout(500 * time.Millisecond)
+	gw.Stdin = strings.NewReader("")
+
+	err := gw.Run()
+	assert.True(t, errors.Is(err, gitw.ErrTimeout))
+
+	bs, err := os.ReadFile(pidFile)
+	assert.NoErr(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(bs)))
+	assert.NoErr(t, err)
+
+	// the child of git is killed
+	deadline := time.Now().Add(3 * time.Second)
+	for procAlive(pid) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.False(t, procAlive(pid))
+}
+
+// check the process is running. the zombie process is not running.
+func procAlive(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+
+	bs, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	return err != nil || !strings.Contains(string(bs), ") Z ")
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package gitw
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setProcGroup kill the git process tree by taskkill on context canceled.
+func setProcGroup(c *exec.Cmd) (restore func()) {
+	c.Cancel = func() error {
+		err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(c.Process.Pid)).Run()
+		if err != nil {
+			return c.Process.Kill()
+		}
+		return nil
+	}
+	return nil
+}
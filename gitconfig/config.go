@@ -0,0 +1,287 @@
+package gitconfig
+
+import (
+	"strings"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// Entry a config item
+type Entry struct {
+	// Section name, is lower case. eg: core, remote
+	Section string
+	// Subsection name, is case-sensitive. eg: origin of [remote "origin"]
+	Subsection string
+	// Name of the key, is lower case. eg: url
+	Name  string
+	Value string
+	// NoValue the key has no value. eg: "[core] bare", it is implicit true
+	NoValue bool
+	// Scope of the entry. eg: ScopeLocal
+	Scope string
+	// Origin file path of the entry. is empty on the scope is ScopeCommand
+	Origin string
+	// Line number in the origin file
+	Line int
+}
+
+// Key get the full key. eg: remote.origin.url
+func (e *Entry) Key() string {
+	if e.Subsection == "" {
+		return e.Section + "." + e.Name
+	}
+	return e.Section + "." + e.Subsection + "." + e.Name
+}
+
+// Bool get the value as bool. see ParseBool()
+func (e *Entry) Bool() (bool, error) {
+	if e.NoValue {
+		return true, nil
+	}
+	return ParseBool(e.Value)
+}
+
+// Int get the value as int, support the unit suffix. see ParseInt()
+func (e *Entry) Int() (int64, error) {
+	return ParseInt(e.Value)
+}
+
+// Color get the value as ANSI color escape sequence. see ParseColor()
+func (e *Entry) Color() (string, error) {
+	return ParseColor(e.Value)
+}
+
+// String get the entry string. eg: "remote.origin.url=https://github.com/gookit/gitw"
+func (e *Entry) String() string {
+	if e.NoValue {
+		return e.Key()
+	}
+	return e.Key() + "=" + e.Value
+}
+
+// SplitKey split the key to section, subsection and name. the section and name will be lower case.
+//
+// eg: "remote.origin.url" => "remote", "origin", "url"
+func SplitKey(key string) (section, subsection, name string, err error) {
+	first, last := strings.IndexByte(key, '.'), strings.LastIndexByte(key, '.')
+	if first <= 0 || last == len(key)-1 {
+		return "", "", "", errorx.Rawf("invalid config key %q, must be section[.subsection].name", key)
+	}
+
+	section, name = strings.ToLower(key[:first]), strings.ToLower(key[last+1:])
+	if !isValidName(section) || !isValidName(name) {
+		return "", "", "", errorx.Rawf("invalid config key %q", key)
+	}
+
+	if first != last {
+		subsection = key[first+1 : last]
+	}
+	return
+}
+
+// the name must start with a letter, and only contains the allowed chars.
+func isValidName(name string) bool {
+	if name == "" || !isAlpha(name[0]) {
+		return false
+	}
+
+	for i := 1; i < len(name); i++ {
+		if !isKeyChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeKey get the normalized key, the section and name are lower case. returns empty on invalid.
+func NormalizeKey(key string) string {
+	section, subsection, name, err := SplitKey(key)
+	if err != nil {
+		return ""
+	}
+	return (&Entry{Section: section, Subsection: subsection, Name: name}).Key()
+}
+
+// Config the merged config of multi files and scopes. the later entry has higher priority.
+type Config struct {
+	entries []*Entry
+	// key => entries
+	index map[string][]*Entry
+}
+
+// New create an empty Config
+func New() *Config {
+	return &Config{index: make(map[string][]*Entry)}
+}
+
+// Add entries to the config
+func (c *Config) Add(ents ...*Entry) {
+	for _, e := range ents {
+		c.entries = append(c.entries, e)
+		key := e.Key()
+		c.index[key] = append(c.index[key], e)
+	}
+}
+
+// AddFile add the entries of the file with scope, will not load the includes.
+func (c *Config) AddFile(f *File, scope string) {
+	for _, e := range f.Entries() {
+		e.Scope, e.Origin = scope, f.Path
+		c.Add(e)
+	}
+}
+
+// All get all entries in the order of load
+func (c *Config) All() []*Entry { return c.entries }
+
+// Len get the entries number
+func (c *Config) Len() int { return len(c.entries) }
+
+// Entries get all entries of the key. eg: remote.origin.fetch
+func (c *Config) Entries(key string) []*Entry {
+	return c.index[NormalizeKey(key)]
+}
+
+// Entry get the last entry of the key, it has the highest priority. returns nil on not found.
+func (c *Config) Entry(key string) *Entry {
+	ents := c.Entries(key)
+	if len(ents) == 0 {
+		return nil
+	}
+	return ents[len(ents)-1]
+}
+
+// Has check the key exists
+func (c *Config) Has(key string) bool {
+	return len(c.Entries(key)) > 0
+}
+
+// Lookup get the value of the key, ok is false on not found.
+func (c *Config) Lookup(key string) (string, bool) {
+	if e := c.Entry(key); e != nil {
+		return e.Value, true
+	}
+	return "", false
+}
+
+// Get the value of the key, returns empty on not found.
+func (c *Config) Get(key string) string {
+	val, _ := c.Lookup(key)
+	return val
+}
+
+// GetAll get all values of the multi-valued key. eg: remote.origin.fetch
+func (c *Config) GetAll(key string) []string {
+	ents := c.Entries(key)
+	if len(ents) == 0 {
+		return nil
+	}
+
+	vals := make([]string, 0, len(ents))
+	for _, e := range ents {
+		vals = append(vals, e.Value)
+	}
+	return vals
+}
+
+// Bool get the value as bool, returns def on not found or invalid.
+func (c *Config) Bool(key string, def bool) bool {
+	if e := c.Entry(key); e != nil {
+		if val, err := e.Bool(); err == nil {
+			return val
+		}
+	}
+	return def
+}
+
+// Int get the value as int, returns def on not found or invalid.
+func (c *Config) Int(key string, def int64) int64 {
+	if e := c.Entry(key); e != nil {
+		if val, err := e.Int(); err == nil {
+			return val
+		}
+	}
+	return def
+}
+
+// Subsections get the subsection names of the section, in the order of first appear.
+//
+// eg: Subsections("remote") => ["origin", "upstream"]
+func (c *Config) Subsections(section string) []string {
+	section = strings.ToLower(section)
+	seen := make(map[string]bool)
+
+	var names []string
+	for _, e := range c.entries {
+		if e.Section == section && e.Subsection != "" && !seen[e.Subsection] {
+			seen[e.Subsection] = true
+			names = append(names, e.Subsection)
+		}
+	}
+	return names
+}
+
+// Scope get a new Config only contains the entries of the scope. eg: ScopeGlobal
+func (c *Config) Scope(scope string) *Config {
+	nc := New()
+	for _, e := range c.entries {
+		if e.Scope == scope {
+			nc.Add(e)
+		}
+	}
+	return nc
+}
+
+// InsteadOf rewrite the URL by the "url.<base>.insteadOf" config, the longest match will be used.
+//
+// returns the raw URL on not matched.
+func (c *Config) InsteadOf(url string) string {
+	if newURL, ok := c.rewriteURL(url, "insteadof"); ok {
+		return newURL
+	}
+	return url
+}
+
+// PushInsteadOf rewrite the URL by the "url.<base>.pushInsteadOf" config, ok is false on not matched.
+func (c *Config) PushInsteadOf(url string) (string, bool) {
+	return c.rewriteURL(url, "pushinsteadof")
+}
+
+func (c *Config) rewriteURL(url, name string) (string, bool) {
+	var base, prefix string
+	for _, e := range c.entries {
+		if e.Section != "url" || e.Name != name || e.Subsection == "" {
+			continue
+		}
+
+		if strings.HasPrefix(url, e.Value) && len(e.Value) > len(prefix) {
+			base, prefix = e.Subsection, e.Value
+		}
+	}
+
+	if prefix == "" {
+		return url, false
+	}
+	return base + url[len(prefix):], true
+}
+
+// ParseList parse the output of: git config --list -z
+//
+// the entries has no scope and origin. output eg: "core.bare\nfalse\x00core.filemode\x00"
+func ParseList(out string) (*Config, error) {
+	cfg := New()
+	for _, item := range strings.Split(out, "\x00") {
+		if item = strings.TrimLeft(item, "\n"); item == "" {
+			continue
+		}
+
+		key, val, hasVal := strings.Cut(item, "\n")
+		section, subsection, name, err := SplitKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Add(&Entry{Section: section, Subsection: subsection, Name: name, Value: val, NoValue: !hasVal})
+	}
+	return cfg, nil
+}
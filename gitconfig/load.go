@@ -0,0 +1,339 @@
+package gitconfig
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// scopes of the config, same as: git config --show-scope
+const (
+	ScopeSystem   = "system"
+	ScopeGlobal   = "global"
+	ScopeLocal    = "local"
+	ScopeWorktree = "worktree"
+	ScopeCommand  = "command"
+)
+
+// max depth for the include files, same as git.
+const maxIncludeDepth = 10
+
+// Options for load the config. see Load()
+type Options struct {
+	// GitDir the git data dir of the current worktree. is empty on not in a repo,
+	// only the system, global and command scopes will be loaded.
+	GitDir string
+	// CommonDir the common dir of worktrees, the local config file in it. default is GitDir
+	CommonDir string
+	// Branch current branch short name, for match the includeIf "onbranch:" condition.
+	Branch string
+	// Overrides the config from command line, format: "key=value". eg: git -c user.name=inhere
+	Overrides []string
+	// Getenv custom func to read env, default is os.Getenv
+	Getenv func(key string) string
+	// NoSystem not load the system config
+	NoSystem bool
+	// NoGlobal not load the global config
+	NoGlobal bool
+	// NoIncludes not load the include.path and includeIf.*.path files
+	NoIncludes bool
+}
+
+func (o *Options) getenv(key string) string {
+	if o.Getenv != nil {
+		return o.Getenv(key)
+	}
+	return os.Getenv(key)
+}
+
+// Load the merged config of system, global, local, worktree and command scopes.
+// the later scope has higher priority, same as git.
+//
+// NOTE: the includeIf "hasconfig:" condition is not supported, will be skipped.
+//
+// Usage:
+//
+//	cfg, err := gitconfig.Load(&gitconfig.Options{GitDir: "/path/to/repo/.git"})
+//	name := cfg.Get("user.name")
+func Load(opts *Options) (*Config, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	l := &loader{opts: opts, cfg: New()}
+	for _, sf := range l.scopeFiles() {
+		if err := l.loadFile(sf.path, sf.scope, 0); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	if err := l.loadCommand(); err != nil {
+		return nil, err
+	}
+	return l.cfg, nil
+}
+
+type loader struct {
+	opts *Options
+	cfg  *Config
+}
+
+type scopeFile struct {
+	scope, path string
+}
+
+// get the config files of the scopes. see git help config: FILES
+func (l *loader) scopeFiles() []scopeFile {
+	var files []scopeFile
+	getenv := l.opts.getenv
+
+	if !l.opts.NoSystem && !isTrue(getenv("GIT_CONFIG_NOSYSTEM")) {
+		path := getenv("GIT_CONFIG_SYSTEM")
+		if path == "" {
+			path = "/etc/gitconfig"
+		}
+		files = append(files, scopeFile{ScopeSystem, path})
+	}
+
+	if !l.opts.NoGlobal {
+		if path := getenv("GIT_CONFIG_GLOBAL"); path != "" {
+			files = append(files, scopeFile{ScopeGlobal, path})
+		} else {
+			home := getenv("HOME")
+			xdg := getenv("XDG_CONFIG_HOME")
+			if xdg == "" && home != "" {
+				xdg = filepath.Join(home, ".config")
+			}
+
+			if xdg != "" {
+				files = append(files, scopeFile{ScopeGlobal, filepath.Join(xdg, "git", "config")})
+			}
+			if home != "" {
+				files = append(files, scopeFile{ScopeGlobal, filepath.Join(home, ".gitconfig")})
+			}
+		}
+	}
+
+	if l.opts.GitDir != "" {
+		commonDir := l.opts.CommonDir
+		if commonDir == "" {
+			commonDir = l.opts.GitDir
+		}
+		files = append(files, scopeFile{ScopeLocal, filepath.Join(commonDir, "config")})
+	}
+	return files
+}
+
+// load the config file and the include files of it.
+func (l *loader) loadFile(path, scope string, depth int) error {
+	if depth > maxIncludeDepth {
+		return errorx.Rawf("exceeded maximum include depth(%d) on load %s", maxIncludeDepth, path)
+	}
+
+	f, err := ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range f.Entries() {
+		e.Scope, e.Origin = scope, path
+		l.cfg.Add(e)
+
+		if incPath := l.includePath(e, filepath.Dir(path)); incPath != "" {
+			// missing include file is ignored, same as git
+			if err := l.loadFile(incPath, scope, depth+1); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	// the worktree config is enabled by the local config
+	if scope == ScopeLocal && depth == 0 && l.cfg.Bool("extensions.worktreeConfig", false) {
+		err = l.loadFile(filepath.Join(l.opts.GitDir, "config.worktree"), ScopeWorktree, 0)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// load the command line config from GIT_CONFIG_COUNT env and Options.Overrides
+func (l *loader) loadCommand() error {
+	var pairs []string
+	if str := l.opts.getenv("GIT_CONFIG_COUNT"); str != "" {
+		count, err := strconv.Atoi(str)
+		if err != nil || count < 0 {
+			return errorx.Rawf("invalid GIT_CONFIG_COUNT value %q", str)
+		}
+
+		for i := 0; i < count; i++ {
+			idx := strconv.Itoa(i)
+			key := l.opts.getenv("GIT_CONFIG_KEY_" + idx)
+			if key == "" {
+				return errorx.Rawf("missing config key GIT_CONFIG_KEY_%d", i)
+			}
+			pairs = append(pairs, key+"="+l.opts.getenv("GIT_CONFIG_VALUE_"+idx))
+		}
+	}
+
+	for _, pair := range append(pairs, l.opts.Overrides...) {
+		key, val, hasVal := strings.Cut(pair, "=")
+		section, subsection, name, err := SplitKey(key)
+		if err != nil {
+			return err
+		}
+
+		l.cfg.Add(&Entry{
+			Section:    section,
+			Subsection: subsection,
+			Name:       name,
+			Value:      val,
+			NoValue:    !hasVal,
+			Scope:      ScopeCommand,
+		})
+	}
+	return nil
+}
+
+// get the include path of the entry, returns empty on not an include entry or condition not matched.
+func (l *loader) includePath(e *Entry, baseDir string) string {
+	if l.opts.NoIncludes || e.Name != "path" || e.Value == "" {
+		return ""
+	}
+
+	switch e.Section {
+	case "include":
+		if e.Subsection != "" {
+			return ""
+		}
+	case "includeif":
+		if !l.matchCondition(e.Subsection, baseDir) {
+			return ""
+		}
+	default:
+		return ""
+	}
+
+	path := l.expandHome(e.Value)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return path
+}
+
+// match the includeIf condition. eg: "gitdir:~/work/", "gitdir/i:C:/work/", "onbranch:fea/"
+func (l *loader) matchCondition(cond, baseDir string) bool {
+	typ, pattern, ok := strings.Cut(cond, ":")
+	if !ok || pattern == "" {
+		return false
+	}
+
+	switch typ {
+	case "gitdir", "gitdir/i":
+		if l.opts.GitDir == "" {
+			return false
+		}
+
+		pattern = l.expandHome(pattern)
+		if s, ok := strings.CutPrefix(pattern, "./"); ok {
+			pattern = filepath.ToSlash(baseDir) + "/" + s
+		} else if !filepath.IsAbs(pattern) && !strings.HasPrefix(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "**"
+		}
+
+		icase := typ == "gitdir/i"
+		gitDir := filepath.ToSlash(l.opts.GitDir)
+		if wildMatch(pattern, gitDir, icase) {
+			return true
+		}
+
+		// also match the real path, same as git
+		real, err := filepath.EvalSymlinks(l.opts.GitDir)
+		return err == nil && wildMatch(pattern, filepath.ToSlash(real), icase)
+	case "onbranch":
+		if l.opts.Branch == "" {
+			return false
+		}
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "**"
+		}
+		return wildMatch(pattern, l.opts.Branch, false)
+	}
+	return false
+}
+
+func (l *loader) expandHome(path string) string {
+	if s, ok := strings.CutPrefix(path, "~/"); ok {
+		if home := l.opts.getenv("HOME"); home != "" {
+			return filepath.ToSlash(home) + "/" + s
+		}
+	}
+	return path
+}
+
+func isTrue(s string) bool {
+	val, err := ParseBool(s)
+	return err == nil && val
+}
+
+// wildMatch match the path by pattern, the "*" not match "/", "**" match any dirs. like wildmatch of git.
+func wildMatch(pattern, path string, icase bool) bool {
+	var sb strings.Builder
+	if icase {
+		sb.WriteString("(?i)")
+	}
+	sb.WriteByte('^')
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" match zero or more dirs
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				c = pattern[i]
+			}
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteByte('$')
+
+	re, err := regexp.Compile(sb.String())
+	return err == nil && re.MatchString(path)
+}
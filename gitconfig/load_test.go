@@ -0,0 +1,111 @@
+package gitconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/gitw/gitconfig"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func writeFile(t *testing.T, path, contents string) {
+	assert.NoErr(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoErr(t, os.WriteFile(path, []byte(contents), 0644))
+}
+
+func TestLoad(t *testing.T) {
+	home := t.TempDir()
+	gitDir := filepath.Join(home, "work", "gitw", ".git")
+
+	writeFile(t, filepath.Join(home, "system.cfg"), "[core]\n\tautocrlf = input\n[user]\n\tname = system\n")
+	writeFile(t, filepath.Join(home, ".config", "git", "config"), "[user]\n\tname = xdg\n")
+	writeFile(t, filepath.Join(home, ".gitconfig"), `[user]
+	name = global
+[include]
+	path = inc/common.cfg
+	path = not-exists.cfg
+[includeIf "gitdir:~/work/"]
+	path = ~/work.cfg
+[includeIf "gitdir:~/other/"]
+	path = ~/other.cfg
+[includeIf "onbranch:fea/"]
+	path = ~/fea.cfg
+[url "git@github.com:"]
+	insteadOf = https://github.com/
+	pushInsteadOf = gh:
+`)
+	writeFile(t, filepath.Join(home, "inc", "common.cfg"), "[color]\n\tui = auto\n")
+	writeFile(t, filepath.Join(home, "work.cfg"), "[user]\n\temail = work@example.com\n")
+	writeFile(t, filepath.Join(home, "other.cfg"), "[user]\n\temail = other@example.com\n")
+	writeFile(t, filepath.Join(home, "fea.cfg"), "[core]\n\teditor = vim\n")
+	writeFile(t, filepath.Join(gitDir, "config"), `[core]
+	bare = false
+[extensions]
+	worktreeConfig = true
+[remote "origin"]
+	url = https://github.com/gookit/gitw.git
+`)
+	writeFile(t, filepath.Join(gitDir, "config.worktree"), "[core]\n\tsparseCheckout = true\n")
+
+	env := map[string]string{
+		"HOME":               home,
+		"GIT_CONFIG_SYSTEM":  filepath.Join(home, "system.cfg"),
+		"GIT_CONFIG_COUNT":   "1",
+		"GIT_CONFIG_KEY_0":   "user.name",
+		"GIT_CONFIG_VALUE_0": "env",
+	}
+	opts := &gitconfig.Options{
+		GitDir:    gitDir,
+		Branch:    "fea/config",
+		Overrides: []string{"core.quotePath=false", "core.fsync"},
+		Getenv: func(key string) string {
+			return env[key]
+		},
+	}
+
+	cfg, err := gitconfig.Load(opts)
+	assert.NoErr(t, err)
+
+	assert.Eq(t, "env", cfg.Get("user.name"))
+	assert.Eq(t, []string{"system", "xdg", "global", "env"}, cfg.GetAll("user.name"))
+	assert.Eq(t, "input", cfg.Get("core.autocrlf"))
+	assert.Eq(t, "auto", cfg.Get("color.ui"))
+	assert.Eq(t, "work@example.com", cfg.Get("user.email"))
+	assert.Eq(t, "vim", cfg.Get("core.editor"))
+	assert.False(t, cfg.Bool("core.quotepath", true))
+	assert.True(t, cfg.Bool("core.fsync", false))
+
+	// scope and origin
+	e := cfg.Entry("color.ui")
+	assert.Eq(t, gitconfig.ScopeGlobal, e.Scope)
+	assert.Eq(t, filepath.Join(home, "inc", "common.cfg"), e.Origin)
+	assert.Eq(t, 2, e.Line)
+	assert.Eq(t, gitconfig.ScopeSystem, cfg.Entries("user.name")[0].Scope)
+	assert.Eq(t, gitconfig.ScopeCommand, cfg.Entry("user.name").Scope)
+	assert.Eq(t, gitconfig.ScopeWorktree, cfg.Entry("core.sparseCheckout").Scope)
+	assert.Eq(t, filepath.Join(gitDir, "config"), cfg.Entry("core.bare").Origin)
+	assert.Eq(t, []string{"xdg", "global"}, cfg.Scope(gitconfig.ScopeGlobal).GetAll("user.name"))
+
+	// url rewrite
+	url := cfg.Get("remote.origin.url")
+	assert.Eq(t, "git@github.com:gookit/gitw.git", cfg.InsteadOf(url))
+	assert.Eq(t, "git@gitee.com:gookit/gitw.git", cfg.InsteadOf("git@gitee.com:gookit/gitw.git"))
+	pushURL, ok := cfg.PushInsteadOf("gh:gookit/gitw.git")
+	assert.True(t, ok)
+	assert.Eq(t, "git@github.com:gookit/gitw.git", pushURL)
+
+	// not match the conditions
+	opts.GitDir, opts.Branch = filepath.Join(home, "other", "repo", ".git"), "main"
+	opts.NoSystem = true
+	cfg, err = gitconfig.Load(opts)
+	assert.NoErr(t, err)
+	assert.Eq(t, "other@example.com", cfg.Get("user.email"))
+	assert.False(t, cfg.Has("core.editor"))
+	assert.False(t, cfg.Has("core.autocrlf"))
+
+	// include self
+	writeFile(t, filepath.Join(home, "inc", "common.cfg"), "[include]\n\tpath = common.cfg\n")
+	_, err = gitconfig.Load(opts)
+	assert.ErrMsgContains(t, err, "exceeded maximum include depth")
+}
@@ -0,0 +1,354 @@
+// Package gitconfig provide the parser and writer of the git config files,
+// and load the merged config of the system, global, local, worktree scopes.
+package gitconfig
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+type nodeKind uint8
+
+const (
+	// blank line or comment line
+	nodeBlank nodeKind = iota
+	nodeSection
+	nodeEntry
+)
+
+// node of the config file. join the raw text of all nodes will get the file contents.
+type node struct {
+	kind nodeKind
+	// raw text of the node, contains the newline. will be rebuilt on modified.
+	raw string
+	// start line number
+	line int
+	// section name is lower case. subsection is case-sensitive
+	section    string
+	subsection string
+	// name the key name of the entry, keep the raw case
+	name    string
+	value   string
+	noValue bool
+	// inline comment of the entry. eg: "# some comment"
+	comment string
+}
+
+// File a git config file, keep the comments and formatting for write back.
+type File struct {
+	// Path of the file, is empty on parsed from contents.
+	Path  string
+	nodes []*node
+}
+
+// ReadFile read and parse the git config file.
+func ReadFile(path string) (*File, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := Parse(bs)
+	if err != nil {
+		return nil, errorx.Wrapf(err, "parse config file %s", path)
+	}
+	f.Path = path
+	return f, nil
+}
+
+// Parse the git config contents.
+//
+// Contents eg:
+//
+//	[core]
+//		bare = false
+//	[remote "origin"]
+//		url = git@github.com:gookit/gitw.git
+//		fetch = +refs/heads/*:refs/remotes/origin/*
+func Parse(data []byte) (*File, error) {
+	p := &parser{data: string(data), line: 1}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return &File{nodes: p.nodes}, nil
+}
+
+// Entries get all entries of the file, without the scope and origin.
+func (f *File) Entries() []*Entry {
+	ents := make([]*Entry, 0, len(f.nodes))
+	for _, n := range f.nodes {
+		if n.kind == nodeEntry {
+			ents = append(ents, n.entry())
+		}
+	}
+	return ents
+}
+
+func (n *node) entry() *Entry {
+	return &Entry{
+		Section:    n.section,
+		Subsection: n.subsection,
+		Name:       strings.ToLower(n.name),
+		Value:      n.value,
+		NoValue:    n.noValue,
+		Line:       n.line,
+	}
+}
+
+type parser struct {
+	data string
+	pos  int
+	line int
+	// current section
+	section    string
+	subsection string
+	nodes      []*node
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return errorx.Rawf("line %d: "+format, append([]any{p.line}, args...)...)
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.data) }
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && (p.data[p.pos] == ' ' || p.data[p.pos] == '\t' || p.isCR()) {
+		p.pos++
+	}
+}
+
+// the '\r' of the "\r\n" line ending
+func (p *parser) isCR() bool {
+	return p.data[p.pos] == '\r' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '\n'
+}
+
+// skip to next line, include the newline
+func (p *parser) skipLine() {
+	for !p.eof() {
+		c := p.data[p.pos]
+		p.pos++
+		if c == '\n' {
+			p.line++
+			return
+		}
+	}
+}
+
+func (p *parser) parse() error {
+	// UTF-8 BOM
+	if strings.HasPrefix(p.data, "\xef\xbb\xbf") {
+		p.pos = 3
+	}
+
+	for !p.eof() {
+		start, line := p.pos, p.line
+		n := &node{kind: nodeBlank, line: line}
+
+		p.skipSpaces()
+		if !p.eof() {
+			switch c := p.data[p.pos]; {
+			case c == '\n', c == '#', c == ';':
+				p.skipLine()
+			case c == '[':
+				if err := p.parseSection(n); err != nil {
+					return err
+				}
+			case isAlpha(c):
+				if err := p.parseEntry(n); err != nil {
+					return err
+				}
+			default:
+				return p.errorf("invalid char %q", c)
+			}
+		}
+
+		n.raw = p.data[start:p.pos]
+		if n.kind == nodeBlank && len(p.nodes) > 0 && p.nodes[len(p.nodes)-1].kind == nodeBlank {
+			// merge the continuous blank lines
+			p.nodes[len(p.nodes)-1].raw += n.raw
+			continue
+		}
+		p.nodes = append(p.nodes, n)
+	}
+	return nil
+}
+
+// parse section header. eg: [core], [remote "origin"], [branch.main](deprecated)
+func (p *parser) parseSection(n *node) error {
+	p.pos++ // skip '['
+	start := p.pos
+	for !p.eof() && isSectionChar(p.data[p.pos]) {
+		p.pos++
+	}
+
+	name := p.data[start:p.pos]
+	if name == "" {
+		return p.errorf("invalid section header, missing the section name")
+	}
+
+	var sub string
+	if !p.eof() && (p.data[p.pos] == ' ' || p.data[p.pos] == '\t') {
+		p.skipSpaces()
+		if p.eof() || p.data[p.pos] != '"' {
+			return p.errorf("invalid section header, the subsection must be quoted")
+		}
+
+		var sb strings.Builder
+		for p.pos++; ; p.pos++ {
+			if p.eof() || p.data[p.pos] == '\n' {
+				return p.errorf("invalid section header, unterminated subsection")
+			}
+
+			c := p.data[p.pos]
+			if c == '"' {
+				p.pos++
+				break
+			}
+			if c == '\\' && p.pos+1 < len(p.data) && p.data[p.pos+1] != '\n' {
+				p.pos++
+				c = p.data[p.pos]
+			}
+			sb.WriteByte(c)
+		}
+		sub = sb.String()
+	} else if idx := strings.IndexByte(name, '.'); idx > 0 {
+		// deprecated syntax: [section.subsection], the subsection is case-insensitive
+		name, sub = name[:idx], strings.ToLower(name[idx+1:])
+	}
+
+	if p.eof() || p.data[p.pos] != ']' {
+		return p.errorf("invalid section header, missing the ']'")
+	}
+	p.pos++
+
+	n.kind, n.section, n.subsection = nodeSection, strings.ToLower(name), sub
+	p.section, p.subsection = n.section, n.subsection
+
+	// the newline after header. NOTE: entry or comment can be after header on same line.
+	p.skipSpaces()
+	if !p.eof() && p.data[p.pos] == '\n' {
+		p.skipLine()
+	}
+	return nil
+}
+
+// parse entry line. eg: "name = value", "name"
+func (p *parser) parseEntry(n *node) error {
+	if p.section == "" {
+		return p.errorf("the key must be in a section")
+	}
+
+	start := p.pos
+	for !p.eof() && isKeyChar(p.data[p.pos]) {
+		p.pos++
+	}
+
+	n.kind, n.name = nodeEntry, p.data[start:p.pos]
+	n.section, n.subsection = p.section, p.subsection
+
+	p.skipSpaces()
+	if p.eof() || p.data[p.pos] == '\n' {
+		// no value, it is implicit true
+		n.noValue = true
+	} else if p.data[p.pos] != '=' {
+		return p.errorf("invalid key %q, expect '=' after the key", n.name+string(p.data[p.pos]))
+	} else {
+		p.pos++
+		if err := p.parseValue(n); err != nil {
+			return err
+		}
+	}
+
+	// inline comment
+	if !p.eof() && (p.data[p.pos] == '#' || p.data[p.pos] == ';') {
+		start = p.pos
+		p.skipLine()
+		n.comment = strings.TrimRight(p.data[start:p.pos], "\r\n")
+		return nil
+	}
+
+	p.skipLine()
+	return nil
+}
+
+// parse the value, support quotes, escapes and line continuation.
+func (p *parser) parseValue(n *node) error {
+	p.skipSpaces()
+
+	var sb strings.Builder
+	var quoted bool
+	// the whitespaces are not quoted, the trailing will be trimmed.
+	var spaces string
+
+	for ; !p.eof(); p.pos++ {
+		c := p.data[p.pos]
+		if c == '\n' || p.isCR() {
+			break
+		}
+		if !quoted && (c == '#' || c == ';') {
+			break
+		}
+
+		if !quoted && (c == ' ' || c == '\t') {
+			spaces += string(c)
+			continue
+		}
+
+		sb.WriteString(spaces)
+		spaces = ""
+
+		switch c {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			p.pos++
+			if p.eof() {
+				return p.errorf("invalid escape at end of file")
+			}
+
+			switch p.data[p.pos] {
+			case '\n':
+				// line continuation
+				p.line++
+			case '\r':
+				if !p.isCR() {
+					return p.errorf("invalid escape char '\\r'")
+				}
+				p.pos++
+				p.line++
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'b':
+				sb.WriteByte('\b')
+			case '"', '\\':
+				sb.WriteByte(p.data[p.pos])
+			default:
+				return p.errorf("invalid escape char %q", p.data[p.pos])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	if quoted {
+		return p.errorf("unterminated quoted value")
+	}
+	n.value = sb.String()
+	return nil
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isKeyChar(c byte) bool {
+	return isAlpha(c) || (c >= '0' && c <= '9') || c == '-'
+}
+
+func isSectionChar(c byte) bool {
+	return isKeyChar(c) || c == '.'
+}
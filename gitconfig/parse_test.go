@@ -0,0 +1,190 @@
+package gitconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/gitw/gitconfig"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+var testContents = `# the config file
+[core]
+	bare = false ; inline comment
+	FileMode
+[remote "origin"]
+	url = git@github.com:gookit/gitw.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+	fetch = +refs/tags/*:refs/tags/*
+[alias] lg = "log --oneline  # not comment" ; comment
+	co = \
+checkout
+	msg = "tab\there\nnew line" trailing
+[branch.Main]
+	remote = origin
+[remote "a\"b"]
+	url =
+`
+
+func TestParse(t *testing.T) {
+	f, err := gitconfig.Parse([]byte(testContents))
+	assert.NoErr(t, err)
+	assert.Eq(t, testContents, string(f.Bytes()))
+
+	cfg := gitconfig.New()
+	cfg.AddFile(f, gitconfig.ScopeLocal)
+	assert.Eq(t, 10, cfg.Len())
+
+	assert.Eq(t, "false", cfg.Get("core.bare"))
+	assert.False(t, cfg.Bool("core.bare", true))
+	assert.True(t, cfg.Bool("core.filemode", false))
+	assert.True(t, cfg.Entry("Core.FileMode").NoValue)
+	assert.Eq(t, 4, cfg.Entry("core.filemode").Line)
+	assert.Eq(t, gitconfig.ScopeLocal, cfg.Entry("core.filemode").Scope)
+
+	assert.Eq(t, "git@github.com:gookit/gitw.git", cfg.Get("remote.origin.url"))
+	assert.Eq(t, []string{"+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*"}, cfg.GetAll("remote.origin.fetch"))
+	assert.Empty(t, cfg.GetAll("remote.Origin.fetch"))
+	assert.Eq(t, "log --oneline  # not comment", cfg.Get("alias.lg"))
+	assert.Eq(t, "checkout", cfg.Get("alias.co"))
+	assert.Eq(t, 10, cfg.Entry("alias.co").Line)
+	assert.Eq(t, "tab\there\nnew line trailing", cfg.Get("alias.msg"))
+	assert.Eq(t, "origin", cfg.Get("branch.main.remote"))
+
+	val, ok := cfg.Lookup(`remote.a"b.url`)
+	assert.True(t, ok)
+	assert.Eq(t, "", val)
+	assert.Eq(t, []string{"origin", `a"b`}, cfg.Subsections("remote"))
+
+	// invalid contents
+	for _, s := range []string{"name = val", "[core\n", "[core]\n\tbare = \"false\n", "[core]\n\t1name = val", "[core]\n\tkey = \\x", "[core]\n\tkey ; comment"} {
+		_, err = gitconfig.Parse([]byte(s))
+		assert.Err(t, err, s)
+	}
+}
+
+func TestFile_write(t *testing.T) {
+	f, err := gitconfig.Parse([]byte(testContents))
+	assert.NoErr(t, err)
+
+	assert.NoErr(t, f.Set("core.bare", "true"))
+	assert.NoErr(t, f.Set("core.fileMode", "false"))
+	assert.NoErr(t, f.Set("core.autocrlf", "input"))
+	assert.Err(t, f.Set("remote.origin.fetch", "+refs/heads/main"))
+	assert.NoErr(t, f.Add("remote.origin.pushurl", "git@github.com:inhere/gitw.git"))
+	assert.NoErr(t, f.Set("alias.lg", " log #1"))
+	assert.NoErr(t, f.Set("user.name", "inhere"))
+	assert.NoErr(t, f.ReplaceAll("remote.origin.fetch", "+refs/heads/main:refs/remotes/origin/main"))
+	assert.True(t, f.Unset("alias.co"))
+	assert.False(t, f.Unset("alias.not-exists"))
+	assert.True(t, f.RemoveSection(`remote.a"b`))
+	assert.Err(t, f.Set("invalid", "val"))
+
+	assert.Eq(t, `# the config file
+[core]
+	bare = true ; inline comment
+	FileMode = false
+	autocrlf = input
+[remote "origin"]
+	url = git@github.com:gookit/gitw.git
+	fetch = +refs/heads/main:refs/remotes/origin/main
+	pushurl = git@github.com:inhere/gitw.git
+[alias]
+	lg = " log #1" ; comment
+	msg = "tab\there\nnew line" trailing
+[branch.Main]
+	remote = origin
+[user]
+	name = inhere
+`, string(f.Bytes()))
+
+	// parse again
+	nf, err := gitconfig.Parse(f.Bytes())
+	assert.NoErr(t, err)
+	val, ok := nf.Get("alias.lg")
+	assert.True(t, ok)
+	assert.Eq(t, " log #1", val)
+
+	// save
+	path := filepath.Join(t.TempDir(), "config")
+	assert.NoErr(t, f.SaveTo(path))
+	rf, err := gitconfig.ReadFile(path)
+	assert.NoErr(t, err)
+	assert.Eq(t, f.Bytes(), rf.Bytes())
+
+	assert.NoErr(t, rf.Set("user.email", "in.798@qq.com"))
+	assert.NoErr(t, rf.Save())
+	bs, err := os.ReadFile(path)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), "\temail = in.798@qq.com\n")
+	assert.FileNotExists(t, path+".lock")
+}
+
+func TestFile_write_noTrailingNewline(t *testing.T) {
+	tests := map[string]string{
+		"[core]\n\tbare = false":            "[core]\n\tbare = false\n\tx = 1\n",
+		"[core]\n\tbare = false ; comment":  "[core]\n\tbare = false ; comment\n\tx = 1\n",
+		"[core] bare = false":               "[core] bare = false\n\tx = 1\n",
+		"[core]\n\tbare = false\n# comment": "[core]\n\tbare = false\n\tx = 1\n# comment",
+	}
+
+	for contents, want := range tests {
+		f, err := gitconfig.Parse([]byte(contents))
+		assert.NoErr(t, err)
+		assert.NoErr(t, f.Add("core.x", "1"))
+		assert.Eq(t, want, string(f.Bytes()))
+
+		nf, err := gitconfig.Parse(f.Bytes())
+		assert.NoErr(t, err)
+		val, _ := nf.Get("core.bare")
+		assert.Eq(t, "false", val)
+		val, _ = nf.Get("core.x")
+		assert.Eq(t, "1", val)
+	}
+
+	// append new section after a comment without newline
+	f, err := gitconfig.Parse([]byte("[core]\n\tbare = false\n# comment"))
+	assert.NoErr(t, err)
+	assert.NoErr(t, f.Set("user.name", "inhere"))
+	assert.Eq(t, "[core]\n\tbare = false\n# comment\n[user]\n\tname = inhere\n", string(f.Bytes()))
+}
+
+func TestParseTypes(t *testing.T) {
+	for s, want := range map[string]bool{"yes": true, "On": true, "1": true, "2k": true, "no": false, "": false, "0": false} {
+		val, err := gitconfig.ParseBool(s)
+		assert.NoErr(t, err)
+		assert.Eq(t, want, val, s)
+	}
+	_, err := gitconfig.ParseBool("abc")
+	assert.Err(t, err)
+
+	for s, want := range map[string]int64{"10": 10, "-1": -1, "1k": 1024, "2M": 2 << 20, "1g": 1 << 30} {
+		val, err := gitconfig.ParseInt(s)
+		assert.NoErr(t, err)
+		assert.Eq(t, want, val, s)
+	}
+	_, err = gitconfig.ParseInt("1x")
+	assert.Err(t, err)
+
+	// the expected is same as: git config --type=color --default <value> test.color
+	for s, want := range map[string]string{
+		"red bold":        "\x1b[1;31m",
+		"reset":           "\x1b[m",
+		"normal red":      "\x1b[41m",
+		"brightred blue":  "\x1b[91;44m",
+		"12 default":      "\x1b[94;49m",
+		"#ff0000 ul":      "\x1b[4;38;2;255;0;0m",
+		"nobold nodim ul": "\x1b[4;22m",
+		"200":             "\x1b[38;5;200m",
+		"":                "",
+	} {
+		val, err := gitconfig.ParseColor(s)
+		assert.NoErr(t, err)
+		assert.Eq(t, want, val, s)
+	}
+	_, err = gitconfig.ParseColor("red blue green")
+	assert.Err(t, err)
+	_, err = gitconfig.ParseColor("unknown")
+	assert.Err(t, err)
+}
@@ -0,0 +1,191 @@
+package gitconfig
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// ParseBool parse the bool value like git. true: yes, on, true, 1, and other non-zero number.
+// false: no, off, false, 0 and empty string. the value is case-insensitive.
+func ParseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off", "":
+		return false, nil
+	}
+
+	n, err := ParseInt(s)
+	if err != nil {
+		return false, errorx.Rawf("invalid bool config value %q", s)
+	}
+	return n != 0, nil
+}
+
+// ParseInt parse the int value, support the unit suffix k, m, g(case-insensitive). eg: 1k => 1024
+func ParseInt(s string) (int64, error) {
+	num, unit := strings.TrimSpace(s), int64(1)
+	if num != "" {
+		switch num[len(num)-1] {
+		case 'k', 'K':
+			unit = 1 << 10
+		case 'm', 'M':
+			unit = 1 << 20
+		case 'g', 'G':
+			unit = 1 << 30
+		}
+		if unit > 1 {
+			num = num[:len(num)-1]
+		}
+	}
+
+	n, err := strconv.ParseInt(num, 0, 64)
+	if err != nil || (n != 0 && (n*unit)/unit != n) {
+		return 0, errorx.Rawf("invalid int config value %q", s)
+	}
+	return n * unit, nil
+}
+
+var colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// color attributes and the ANSI codes. the negated code is for the "no" prefix
+var colorAttrs = []struct {
+	name      string
+	code, neg int
+}{
+	{"bold", 1, 22},
+	{"dim", 2, 22},
+	{"italic", 3, 23},
+	{"ul", 4, 24},
+	{"blink", 5, 25},
+	{"reverse", 7, 27},
+	{"strike", 9, 29},
+}
+
+// ParseColor parse the color value to ANSI escape sequence, same as: git config --type=color
+//
+// value format: "[<foreground> [<background>]] [<attribute>...]". eg: "red bold", "#ff0000 ul", "reset"
+//
+//	ParseColor("red bold") // => "\x1b[1;31m"
+func ParseColor(s string) (string, error) {
+	var colors, attrs []string
+	var reset bool
+	var attrSet, negSet uint
+
+	for _, word := range strings.Fields(s) {
+		lower := strings.ToLower(word)
+		if lower == "reset" {
+			reset = true
+			continue
+		}
+
+		if code, ok := parseColorWord(lower, len(colors) == 0); ok {
+			if len(colors) == 2 {
+				return "", errorx.Rawf("invalid color value %q, too many colors", s)
+			}
+			colors = append(colors, code)
+			continue
+		}
+
+		name, neg := lower, false
+		if s, ok := strings.CutPrefix(lower, "no"); ok {
+			name, neg = strings.TrimPrefix(s, "-"), true
+		}
+
+		found := false
+		for i, attr := range colorAttrs {
+			if attr.name == name {
+				if neg {
+					negSet |= 1 << i
+				} else {
+					attrSet |= 1 << i
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", errorx.Rawf("invalid color value %q", s)
+		}
+	}
+
+	if reset {
+		attrs = append(attrs, "")
+	}
+	for i, attr := range colorAttrs {
+		if attrSet&(1<<i) != 0 {
+			attrs = append(attrs, strconv.Itoa(attr.code))
+		}
+	}
+	for i, attr := range colorAttrs {
+		if negSet&(1<<i) != 0 && (i != 1 || negSet&1 == 0) {
+			attrs = append(attrs, strconv.Itoa(attr.neg))
+		}
+	}
+
+	for _, code := range colors {
+		if code != "" {
+			attrs = append(attrs, code)
+		}
+	}
+
+	if len(attrs) == 0 {
+		if reset {
+			return "\x1b[m", nil
+		}
+		return "", nil
+	}
+	return "\x1b[" + strings.Join(attrs, ";") + "m", nil
+}
+
+// parse the color word to ANSI code. "normal" returns empty code.
+func parseColorWord(word string, fg bool) (string, bool) {
+	base := 30
+	if !fg {
+		base = 40
+	}
+
+	switch {
+	case word == "normal":
+		return "", true
+	case word == "default":
+		return strconv.Itoa(base + 9), true
+	case strings.HasPrefix(word, "#"):
+		hex := word[1:]
+		rgb, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return "", false
+		}
+		return strconv.Itoa(base+8) + ";2;" + strconv.Itoa(int(rgb>>16)) + ";" +
+			strconv.Itoa(int(rgb>>8&0xff)) + ";" + strconv.Itoa(int(rgb&0xff)), true
+	}
+
+	name, bright := strings.CutPrefix(word, "bright")
+	for i, cn := range colorNames {
+		if cn == name {
+			if bright {
+				return strconv.Itoa(base + 60 + i), true
+			}
+			return strconv.Itoa(base + i), true
+		}
+	}
+
+	// 256 colors: -1 is normal
+	n, err := strconv.Atoi(word)
+	if err != nil || n < -1 || n > 255 {
+		return "", false
+	}
+
+	switch {
+	case n < 0:
+		return "", true
+	case n < 8:
+		return strconv.Itoa(base + n), true
+	case n < 16:
+		return strconv.Itoa(base + 60 + n - 8), true
+	default:
+		return strconv.Itoa(base+8) + ";5;" + strconv.Itoa(n), true
+	}
+}
@@ -0,0 +1,274 @@
+package gitconfig
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// Bytes get the file contents. the unchanged parts will keep the raw formatting.
+func (f *File) Bytes() []byte {
+	var buf bytes.Buffer
+	_, _ = f.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// WriteTo write the file contents to w
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, n := range f.nodes {
+		size, err := io.WriteString(w, n.raw)
+		total += int64(size)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Save write the contents to the File.Path. will use the lock file "<path>.lock" like git.
+func (f *File) Save() error {
+	if f.Path == "" {
+		return errorx.Raw("the config file path is empty")
+	}
+	return f.SaveTo(f.Path)
+}
+
+// SaveTo write the contents to the path. will create the lock file "<path>.lock",
+// and rename it to the path after written.
+func (f *File) SaveTo(path string) error {
+	lockFile := path + ".lock"
+	fh, err := os.OpenFile(lockFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return errorx.Wrapf(err, "can not lock config file %s", path)
+	}
+
+	_, err = f.WriteTo(fh)
+	if cerr := fh.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(lockFile, path)
+	}
+
+	if err != nil {
+		_ = os.Remove(lockFile)
+		return err
+	}
+	return nil
+}
+
+// Get the value of the key in the file, the last one will be returned.
+func (f *File) Get(key string) (string, bool) {
+	idx := f.findEntries(key)
+	if len(idx) == 0 {
+		return "", false
+	}
+	return f.nodes[idx[len(idx)-1]].value, true
+}
+
+// Set the value of the key, like: git config <key> <value>
+//
+// will return error on the key has multi values, can use ReplaceAll() for it.
+func (f *File) Set(key, value string) error {
+	idx := f.findEntries(key)
+	if len(idx) > 1 {
+		return errorx.Rawf("config key %q has multiple values", key)
+	}
+
+	if len(idx) == 1 {
+		f.setValue(idx[0], value)
+		return nil
+	}
+	return f.Add(key, value)
+}
+
+// ReplaceAll replace all values of the key with one value, like: git config --replace-all <key> <value>
+func (f *File) ReplaceAll(key, value string) error {
+	idx := f.findEntries(key)
+	if len(idx) == 0 {
+		return f.Add(key, value)
+	}
+
+	f.setValue(idx[0], value)
+	f.removeNodes(idx[1:])
+	return nil
+}
+
+// Add a new value for the key, like: git config --add <key> <value>
+//
+// the new entry will be added after the last entry of the key, or at the end of the section.
+// will create the section on not exists.
+func (f *File) Add(key, value string) error {
+	section, subsection, _, err := SplitKey(key)
+	if err != nil {
+		return err
+	}
+
+	// keep the raw case of name
+	n := &node{kind: nodeEntry, section: section, subsection: subsection, name: key[strings.LastIndexByte(key, '.')+1:]}
+	n.setValue(value)
+
+	pos := -1
+	if idx := f.findEntries(key); len(idx) > 0 {
+		pos = idx[len(idx)-1] + 1
+	} else {
+		// after the last entry of the last matched section block
+		var in bool
+		for i, nd := range f.nodes {
+			if nd.kind == nodeSection {
+				if in = nd.section == section && nd.subsection == subsection; in {
+					pos = i + 1
+				}
+			} else if in && nd.kind == nodeEntry {
+				pos = i + 1
+			}
+		}
+	}
+
+	// append new section at end
+	if pos < 0 {
+		f.ensureNewline()
+		header := &node{kind: nodeSection, section: section, subsection: subsection}
+		header.raw = formatSection(key[:strings.IndexByte(key, '.')], subsection)
+		f.nodes = append(f.nodes, header, n)
+		return nil
+	}
+
+	f.breakLine(pos)
+	f.nodes = append(f.nodes[:pos], append([]*node{n}, f.nodes[pos:]...)...)
+	return nil
+}
+
+// Unset remove all values of the key, like: git config --unset-all <key>
+//
+// returns false on the key not exists.
+func (f *File) Unset(key string) bool {
+	idx := f.findEntries(key)
+	f.removeNodes(idx)
+	return len(idx) > 0
+}
+
+// RemoveSection remove the section and all entries of it, like: git config --remove-section <name>
+//
+// name can be "section" or "section.subsection". returns false on the section not exists.
+func (f *File) RemoveSection(name string) bool {
+	section, subsection, _ := strings.Cut(name, ".")
+	section = strings.ToLower(section)
+
+	var idx []int
+	var in bool
+	for i, n := range f.nodes {
+		if n.kind == nodeSection {
+			in = n.section == section && n.subsection == subsection
+		}
+		if in {
+			idx = append(idx, i)
+		}
+	}
+
+	f.removeNodes(idx)
+	return len(idx) > 0
+}
+
+// find the entry node index list of the key
+func (f *File) findEntries(key string) []int {
+	section, subsection, name, err := SplitKey(key)
+	if err != nil {
+		return nil
+	}
+
+	var idx []int
+	for i, n := range f.nodes {
+		if n.kind == nodeEntry && n.section == section && n.subsection == subsection && strings.ToLower(n.name) == name {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+func (f *File) removeNodes(idx []int) {
+	if len(idx) == 0 {
+		return
+	}
+
+	nodes := make([]*node, 0, len(f.nodes)-len(idx))
+	for i, n := range f.nodes {
+		if len(idx) > 0 && idx[0] == i {
+			f.breakLine(i)
+			idx = idx[1:]
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	f.nodes = nodes
+}
+
+// set the entry value of the node
+func (f *File) setValue(i int, value string) {
+	f.breakLine(i)
+	f.nodes[i].setValue(value)
+}
+
+// make sure the node before i is end with newline.
+//
+// the section header and entry maybe on same line. eg: "[core] bare = false".
+// the last entry or comment maybe has no newline at end of file.
+func (f *File) breakLine(i int) {
+	if i <= 0 {
+		return
+	}
+
+	prev := f.nodes[i-1]
+	if prev.raw == "" || strings.HasSuffix(prev.raw, "\n") {
+		return
+	}
+
+	if prev.kind == nodeSection {
+		prev.raw = strings.TrimRight(prev.raw, " \t")
+	}
+	prev.raw += "\n"
+}
+
+// make sure the file contents end with newline
+func (f *File) ensureNewline() {
+	if len(f.nodes) > 0 {
+		if last := f.nodes[len(f.nodes)-1]; last.raw != "" && !strings.HasSuffix(last.raw, "\n") {
+			last.raw += "\n"
+		}
+	}
+}
+
+// set the entry value and rebuild the raw text, the inline comment will be kept.
+func (n *node) setValue(value string) {
+	n.value, n.noValue = value, false
+	n.raw = "\t" + n.name + " = " + formatValue(value)
+	if n.comment != "" {
+		n.raw += " " + n.comment
+	}
+	n.raw += "\n"
+}
+
+// format the section header. eg: [remote "origin"]
+func formatSection(section, subsection string) string {
+	if subsection == "" {
+		return "[" + section + "]\n"
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return "[" + section + " \"" + r.Replace(subsection) + "\"]\n"
+}
+
+var valueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\b", `\b`)
+
+// format the value for write. will quote it on has the leading, trailing space or comment chars.
+func formatValue(value string) string {
+	value = valueEscaper.Replace(value)
+	if value != "" && (value[0] == ' ' || value[len(value)-1] == ' ' || strings.ContainsAny(value, "#;")) {
+		return `"` + value + `"`
+	}
+	return value
+}
@@ -0,0 +1,245 @@
+package gitw
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/fsutil"
+)
+
+// GitDirs the resolved dirs of a git repository.
+type GitDirs struct {
+	// GitDir the git data dir of the current worktree. eg: /repo/.git, /repo/.git/worktrees/feat
+	GitDir string
+	// CommonDir the shared data dir of all worktrees, contains config, refs, objects.
+	// it is same as GitDir on the main worktree.
+	CommonDir string
+	// WorkTree the top level dir of the worktree. is empty on bare repo
+	WorkTree string
+	// Bare repository, has no worktree
+	Bare bool
+}
+
+// IsLinkedWorktree check the git dir is of a linked worktree
+func (d *GitDirs) IsLinkedWorktree() bool {
+	return d.CommonDir != d.GitDir
+}
+
+// the paths are private of each worktree, the others in the common dir.
+// see the common_list in git path.c
+var worktreePaths = []string{"HEAD", "index", "logs/HEAD", "refs/bisect", "refs/worktree", "refs/rewritten"}
+
+// the paths in the common dir
+var commonPaths = []string{
+	"config", "packed-refs", "refs", "objects", "hooks", "info", "logs", "remotes",
+	"branches", "rr-cache", "shallow", "worktrees", "lost-found", "gc.pid", "common",
+}
+
+// Path resolve the path in the git dir, like: git rev-parse --git-path <path>
+//
+// Usage:
+//
+//	dirs.Path("HEAD")           // => GitDir/HEAD
+//	dirs.Path("refs", "heads")  // => CommonDir/refs/heads
+func (d *GitDirs) Path(segments ...string) string {
+	path := filepath.ToSlash(filepath.Join(segments...))
+	if d.IsLinkedWorktree() && isCommonPath(path) {
+		return filepath.Join(d.CommonDir, path)
+	}
+	return filepath.Join(d.GitDir, path)
+}
+
+func isCommonPath(path string) bool {
+	for _, p := range worktreePaths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return false
+		}
+	}
+
+	for _, p := range commonPaths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveGitDir resolve the git dirs from the dir. will walk up the parent dirs to find the repo,
+// like: git rev-parse --show-toplevel.
+//
+// support the GIT_DIR and GIT_WORK_TREE env, gitfile(".git" is a file) of the linked worktree
+// and submodule, commondir and bare repository.
+func ResolveGitDir(dir string) (*GitDirs, error) {
+	return resolveGitDir(dir, os.Getenv)
+}
+
+func resolveGitDir(dir string, getenv func(key string) string) (*GitDirs, error) {
+	if dir == "" {
+		dir = "."
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// use the GIT_DIR env
+	if gitDir := getenv("GIT_DIR"); gitDir != "" {
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(dir, gitDir)
+		}
+		if fsutil.IsFile(gitDir) {
+			if gitDir, err = readGitFile(gitDir); err != nil {
+				return nil, err
+			}
+		}
+
+		if !isGitDataDir(gitDir) {
+			return nil, errorx.Wrapf(ErrNotRepo, "invalid GIT_DIR %q", gitDir)
+		}
+
+		// without GIT_WORK_TREE, the current dir is the top level of worktree.
+		wt := getenv("GIT_WORK_TREE")
+		if wt != "" && !filepath.IsAbs(wt) {
+			wt = filepath.Join(dir, wt)
+		} else if wt == "" {
+			wt = dir
+		}
+		return newGitDirs(gitDir, wt), nil
+	}
+
+	for cur := dir; ; {
+		dotGit := filepath.Join(cur, GitDir)
+		if fsutil.IsDir(dotGit) && isGitDataDir(dotGit) {
+			return newGitDirs(dotGit, cur), nil
+		}
+
+		// gitfile of the linked worktree or submodule
+		if fsutil.IsFile(dotGit) {
+			gitDir, err := readGitFile(dotGit)
+			if err != nil {
+				return nil, err
+			}
+			if !isGitDataDir(gitDir) {
+				return nil, errorx.Wrapf(ErrNotRepo, "invalid gitfile %q, the gitdir not exists", dotGit)
+			}
+			return newGitDirs(gitDir, cur), nil
+		}
+
+		// bare repository
+		if isGitDataDir(cur) {
+			return newGitDirs(cur, ""), nil
+		}
+
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			break
+		}
+		cur = parent
+	}
+
+	return nil, errorx.Wrapf(ErrNotRepo, "not a git repository (or any of the parent directories): %s", dir)
+}
+
+func newGitDirs(gitDir, workTree string) *GitDirs {
+	gitDir = filepath.Clean(gitDir)
+	d := &GitDirs{GitDir: gitDir, CommonDir: gitDir, WorkTree: workTree, Bare: workTree == ""}
+
+	if bs, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common := strings.TrimSpace(string(bs))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitDir, common)
+		}
+		d.CommonDir = filepath.Clean(common)
+	}
+	return d
+}
+
+// check the dir is a git data dir. must have HEAD file, and objects, refs dir or commondir file.
+func isGitDataDir(dir string) bool {
+	if !fsutil.IsFile(filepath.Join(dir, HeadFile)) {
+		return false
+	}
+
+	if fsutil.IsFile(filepath.Join(dir, "commondir")) {
+		return true
+	}
+	return fsutil.IsDir(filepath.Join(dir, "objects")) && fsutil.IsDir(filepath.Join(dir, "refs"))
+}
+
+// readGitFile read the git dir path from the gitfile. eg: "gitdir: ../.git/worktrees/feat"
+//
+// the relative path is relative to the gitfile dir.
+func readGitFile(path string) (string, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	line, _, _ := strings.Cut(string(bs), "\n")
+	dir, ok := strings.CutPrefix(strings.TrimSpace(line), "gitdir:")
+	if dir = strings.TrimSpace(dir); !ok || dir == "" {
+		return "", errorx.Rawf("invalid gitfile %q", path)
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(path), dir)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// -------------------------------------------------
+// resolve on GitWrap
+// -------------------------------------------------
+
+// ResolveGitDir resolve the git dirs of the Workdir. see ResolveGitDir()
+//
+// will apply the "-C", "--git-dir", "--work-tree"(with "=" or as two args) global flags and GIT_DIR, GIT_WORK_TREE in Env.
+func (gw *GitWrap) ResolveGitDir() (*GitDirs, error) {
+	dir := gw.Workdir
+	envs := make(map[string]string, 2)
+
+	for i := 0; i < len(gw.GlobalFlags); i++ {
+		flag := gw.GlobalFlags[i]
+		switch {
+		case flag == "-C" && i+1 < len(gw.GlobalFlags):
+			i++
+			if filepath.IsAbs(gw.GlobalFlags[i]) {
+				dir = gw.GlobalFlags[i]
+			} else {
+				dir = filepath.Join(dir, gw.GlobalFlags[i])
+			}
+		case (flag == "--git-dir" || flag == "--work-tree") && i+1 < len(gw.GlobalFlags):
+			i++
+			if flag == "--git-dir" {
+				envs["GIT_DIR"] = gw.GlobalFlags[i]
+			} else {
+				envs["GIT_WORK_TREE"] = gw.GlobalFlags[i]
+			}
+		case strings.HasPrefix(flag, "--git-dir="):
+			envs["GIT_DIR"] = flag[len("--git-dir="):]
+		case strings.HasPrefix(flag, "--work-tree="):
+			envs["GIT_WORK_TREE"] = flag[len("--work-tree="):]
+		}
+	}
+
+	return resolveGitDir(dir, func(key string) string {
+		if val, ok := envs[key]; ok {
+			return val
+		}
+		return gw.getenv(key)
+	})
+}
+
+// get the env value from the gw.Env, fallback to os env.
+func (gw *GitWrap) getenv(key string) string {
+	// the later one will override the former
+	for i := len(gw.Env) - 1; i >= 0; i-- {
+		if val, ok := strings.CutPrefix(gw.Env[i], key+"="); ok {
+			return val
+		}
+	}
+	return os.Getenv(key)
+}
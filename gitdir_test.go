@@ -0,0 +1,119 @@
+package gitw_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestResolveGitDir(t *testing.T) {
+	r := newTempRepo(t)
+	top, err := filepath.EvalSymlinks(r.Dir())
+	assert.NoErr(t, err)
+
+	// walk up from sub dir
+	sub := filepath.Join(top, "sub", "dir")
+	assert.NoErr(t, os.MkdirAll(sub, 0755))
+	dirs, err := gitw.ResolveGitDir(sub)
+	assert.NoErr(t, err)
+	assert.Eq(t, top, dirs.WorkTree)
+	assert.Eq(t, filepath.Join(top, ".git"), dirs.GitDir)
+	assert.Eq(t, dirs.GitDir, dirs.CommonDir)
+	assert.False(t, dirs.Bare)
+	assert.False(t, dirs.IsLinkedWorktree())
+
+	// linked worktree
+	wtPath := filepath.Join(top, "..", "linked-wt")
+	assert.NoErr(t, r.AddWorktree(wtPath, "", &gitw.WorktreeAddOpts{Detach: true}))
+	dirs, err = gitw.ResolveGitDir(wtPath)
+	assert.NoErr(t, err)
+	assert.True(t, dirs.IsLinkedWorktree())
+	assert.Eq(t, filepath.Join(top, ".git", "worktrees", "linked-wt"), dirs.GitDir)
+	assert.Eq(t, filepath.Join(top, ".git"), dirs.CommonDir)
+	assert.Eq(t, filepath.Join(dirs.GitDir, "HEAD"), dirs.Path("HEAD"))
+	assert.Eq(t, filepath.Join(dirs.GitDir, "logs", "HEAD"), dirs.Path("logs/HEAD"))
+	assert.Eq(t, filepath.Join(dirs.CommonDir, "config"), dirs.Path("config"))
+	assert.Eq(t, filepath.Join(dirs.CommonDir, "refs", "heads"), dirs.Path("refs", "heads"))
+
+	wr := gitw.NewRepo(wtPath)
+	assert.StrContains(t, string(wr.ReadConfig()), "[core]")
+	assert.Eq(t, 40, len(wr.ReadHEAD())-1) // detached HEAD
+	assert.NoErr(t, wr.Err())
+
+	// bare repo
+	bare := filepath.Join(t.TempDir(), "bare.git")
+	assert.NoErr(t, r.Cmd("clone", "-q", "--bare", top, bare).Run())
+	dirs, err = gitw.ResolveGitDir(filepath.Join(bare, "refs"))
+	assert.NoErr(t, err)
+	assert.True(t, dirs.Bare)
+	assert.Eq(t, "", dirs.WorkTree)
+	assert.True(t, gitw.NewRepo(bare).IsGitRepo())
+
+	// not a repo
+	_, err = gitw.ResolveGitDir(t.TempDir())
+	assert.True(t, errors.Is(err, gitw.ErrNotRepo))
+}
+
+func TestGitWrap_ResolveGitDir(t *testing.T) {
+	r := newTempRepo(t)
+	top, err := filepath.EvalSymlinks(r.Dir())
+	assert.NoErr(t, err)
+	other := t.TempDir()
+
+	// GIT_DIR in env
+	gw := gitw.NewWithWorkdir(other).WithEnv("GIT_DIR", filepath.Join(top, ".git"))
+	dirs, err := gw.ResolveGitDir()
+	assert.NoErr(t, err)
+	assert.Eq(t, filepath.Join(top, ".git"), dirs.GitDir)
+	assert.Eq(t, other, dirs.WorkTree)
+	assert.True(t, gw.IsGitRepo())
+
+	// --git-dir and --work-tree flags
+	gw = gitw.NewWithWorkdir(other).WithGlobalFlags("--git-dir="+filepath.Join(top, ".git"), "--work-tree="+top)
+	dirs, err = gw.ResolveGitDir()
+	assert.NoErr(t, err)
+	assert.Eq(t, top, dirs.WorkTree)
+
+	// the flag value as a separate arg
+	gw = gitw.NewWithWorkdir(other).WithGlobalFlags("--git-dir", filepath.Join(top, ".git"), "--work-tree", top)
+	dirs, err = gw.ResolveGitDir()
+	assert.NoErr(t, err)
+	assert.Eq(t, filepath.Join(top, ".git"), dirs.GitDir)
+	assert.Eq(t, top, dirs.WorkTree)
+
+	// -C flag
+	gw = gitw.NewWithWorkdir(filepath.Dir(top)).WithGlobalFlags("-C", filepath.Base(top))
+	assert.Eq(t, filepath.Join(top, ".git"), gw.GitDir())
+
+	// invalid GIT_DIR
+	gw = gitw.NewWithWorkdir(top).WithEnv("GIT_DIR", other)
+	assert.False(t, gw.IsGitRepo())
+	assert.Eq(t, top+"/.git", gw.GitDir())
+}
+
+func TestDataDir(t *testing.T) {
+	r := newTempRepo(t)
+	top, err := filepath.EvalSymlinks(r.Dir())
+	assert.NoErr(t, err)
+
+	oldDir := gitw.Std().Workdir
+	gitw.SetWorkdir(filepath.Join(top))
+	t.Cleanup(func() {
+		gitw.SetWorkdir(oldDir)
+	})
+
+	dir, err := gitw.DataDir()
+	assert.NoErr(t, err)
+	assert.Eq(t, filepath.Join(top, ".git"), dir)
+
+	dir, err = gitw.WorkdirName()
+	assert.NoErr(t, err)
+	assert.Eq(t, top, dir)
+
+	assert.True(t, gitw.HasFile("refs", "heads", "main"))
+	assert.False(t, gitw.HasFile("refs", "stash"))
+}
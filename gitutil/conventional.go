@@ -0,0 +1,226 @@
+package gitutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ConventionalCommit struct of the conventional commit message.
+//
+// Format:
+//
+//	<type>[optional scope][!]: <description>
+//
+//	[optional body]
+//
+//	[optional footer(s)]
+//
+// see https://www.conventionalcommits.org/en/v1.0.0/
+type ConventionalCommit struct {
+	// Emoji the gitmoji prefix. eg: ":sparkles:", "✨"
+	Emoji string
+	// Header the first line, not contains the Emoji
+	Header string
+	// Type of the commit. eg: feat, fix
+	Type string
+	// Scopes of the commit. eg: "feat(api,cli): ..." => [api, cli]
+	Scopes []string
+	// Breaking has "!" marker or a BREAKING CHANGE footer
+	Breaking bool
+	// Description after the ": "
+	Description string
+	// Body of the message, not contains the footers
+	Body string
+	// Footers of the message. eg: "Fixes #123", "BREAKING CHANGE: xx"
+	Footers []Trailer
+}
+
+// Scope get the scopes string. eg: "api,cli"
+func (cc *ConventionalCommit) Scope() string {
+	return strings.Join(cc.Scopes, ",")
+}
+
+// Footer get first footer value by key(case-insensitive)
+func (cc *ConventionalCommit) Footer(key string) string {
+	for _, t := range cc.Footers {
+		if t.IsKey(key) {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// BreakingChange get the breaking change description. returns the Description on only has "!" marker.
+func (cc *ConventionalCommit) BreakingChange() string {
+	if desc := cc.Footer(TrailerBreakingChange); desc != "" {
+		return desc
+	}
+	if desc := cc.Footer("BREAKING-CHANGE"); desc != "" {
+		return desc
+	}
+
+	if cc.Breaking {
+		return cc.Description
+	}
+	return ""
+}
+
+// ParseError for parse the conventional commit message, with the position.
+type ParseError struct {
+	// Line number, start from 1
+	Line int
+	// Column number of the line, start from 1. count by runes
+	Column int
+	// Msg error message
+	Msg string
+}
+
+// Error string
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// gitmoji shortcode prefix. eg: ":sparkles:"
+var gitmojiCodeReg = regexp.MustCompile(`^:[a-z0-9_+-]+:`)
+
+// StripGitmoji remove the gitmoji prefix of the message. returns the emoji and the rest message.
+//
+// eg: ":sparkles: feat: some message" => (":sparkles:", "feat: some message")
+func StripGitmoji(msg string) (emoji, rest string) {
+	if code := gitmojiCodeReg.FindString(msg); code != "" {
+		return code, strings.TrimLeft(msg[len(code):], " ")
+	}
+
+	// unicode emoji. eg: "✨ feat: some message"
+	i := 0
+	for i < len(msg) {
+		r, size := utf8.DecodeRuneInString(msg[i:])
+		if !isEmojiRune(r) {
+			break
+		}
+		i += size
+	}
+	return msg[:i], strings.TrimLeft(msg[i:], " ")
+}
+
+func isEmojiRune(r rune) bool {
+	// variation selector, zero width joiner
+	return r == 0xFE0F || r == 0x200D || r > 0x2000 && unicode.Is(unicode.So, r)
+}
+
+// the footer of conventional commits allow the separator ": " and " #". eg: "Refs #123"
+var conventionalTrailerOpts = &TrailerOpts{Separators: ":#"}
+
+// ParseConventional parse the conventional commit message.
+// returns *ParseError on the message is not a valid conventional commit.
+//
+// Usage:
+//
+//	cc, err := gitutil.ParseConventional("feat(api)!: add new api\n\nBREAKING CHANGE: remove old api")
+func ParseConventional(msg string) (*ConventionalCommit, error) {
+	msg = strings.TrimLeft(strings.ReplaceAll(msg, "\r\n", "\n"), "\n")
+	header, rest, _ := strings.Cut(msg, "\n")
+
+	header = strings.TrimRight(header, " ")
+
+	cc := &ConventionalCommit{}
+	cc.Emoji, cc.Header = StripGitmoji(header)
+	if err := cc.parseHeader(utf8.RuneCountInString(header) - utf8.RuneCountInString(cc.Header)); err != nil {
+		return cc, err
+	}
+
+	if rest == "" {
+		return cc, nil
+	}
+
+	// the body must begin one blank line after the description.
+	if line, _, _ := strings.Cut(rest, "\n"); strings.TrimSpace(line) != "" {
+		return cc, &ParseError{Line: 2, Column: 1, Msg: "header must be followed by a blank line"}
+	}
+
+	var body string
+	body, cc.Footers = conventionalTrailerOpts.Split(msg)
+	if _, after, ok := strings.Cut(body, "\n"); ok {
+		cc.Body = strings.Trim(after, "\n")
+	}
+
+	if cc.Footer(TrailerBreakingChange) != "" || cc.Footer("BREAKING-CHANGE") != "" {
+		cc.Breaking = true
+	}
+	return cc, nil
+}
+
+// parse header: type(scope)!: description. offset is the runes count before the Header.
+func (cc *ConventionalCommit) parseHeader(offset int) error {
+	h := cc.Header
+	newErr := func(idx int, msg string) error {
+		return &ParseError{Line: 1, Column: offset + utf8.RuneCountInString(h[:idx]) + 1, Msg: msg}
+	}
+
+	if h == "" {
+		return newErr(0, "empty commit header")
+	}
+
+	// type: [A-Za-z]+
+	i := 0
+	for i < len(h) && isTypeChar(h[i]) {
+		i++
+	}
+	if i == 0 {
+		return newErr(0, "commit type is required, must be letters")
+	}
+	cc.Type = h[:i]
+
+	// scope: (api,cli)
+	if i < len(h) && h[i] == '(' {
+		end := strings.IndexByte(h[i:], ')')
+		if end < 0 {
+			return newErr(i, "commit scope is not closed, missing ')'")
+		}
+
+		scope := h[i+1 : i+end]
+		if strings.TrimSpace(scope) == "" {
+			return newErr(i+1, "commit scope can not be empty")
+		}
+
+		for _, s := range strings.Split(scope, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				cc.Scopes = append(cc.Scopes, s)
+			}
+		}
+		i += end + 1
+	}
+
+	if i < len(h) && h[i] == '!' {
+		cc.Breaking = true
+		i++
+	}
+
+	if i >= len(h) || h[i] != ':' {
+		return newErr(i, "expect ':' after the commit type or scope")
+	}
+
+	i++
+	if i < len(h) && h[i] != ' ' {
+		return newErr(i, "expect a space after the ':'")
+	}
+
+	cc.Description = strings.TrimSpace(h[i:])
+	if cc.Description == "" {
+		return newErr(i, "commit description can not be empty")
+	}
+	return nil
+}
+
+func isTypeChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// IsConventional check the message is a valid conventional commit
+func IsConventional(msg string) bool {
+	_, err := ParseConventional(msg)
+	return err == nil
+}
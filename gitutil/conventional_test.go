@@ -0,0 +1,79 @@
+package gitutil_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gookit/gitw/gitutil"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParseConventional(t *testing.T) {
+	msg := `:sparkles: feat(api, cli)!: add new query api
+
+some body contents
+more line
+
+Refs #123
+BREAKING CHANGE: remove the old query api
+`
+	cc, err := gitutil.ParseConventional(msg)
+	assert.NoErr(t, err)
+	assert.Eq(t, ":sparkles:", cc.Emoji)
+	assert.Eq(t, "feat(api, cli)!: add new query api", cc.Header)
+	assert.Eq(t, "feat", cc.Type)
+	assert.Eq(t, []string{"api", "cli"}, cc.Scopes)
+	assert.Eq(t, "api,cli", cc.Scope())
+	assert.True(t, cc.Breaking)
+	assert.Eq(t, "add new query api", cc.Description)
+	assert.Eq(t, "some body contents\nmore line", cc.Body)
+	assert.Len(t, cc.Footers, 2)
+	assert.Eq(t, "#123", cc.Footer("refs"))
+	assert.Eq(t, "remove the old query api", cc.BreakingChange())
+
+	// unicode emoji, breaking by footer
+	cc, err = gitutil.ParseConventional("✨ fix: some bug\n\nBREAKING-CHANGE: the desc")
+	assert.NoErr(t, err)
+	assert.Eq(t, "✨", cc.Emoji)
+	assert.Eq(t, "fix", cc.Type)
+	assert.Empty(t, cc.Body)
+	assert.True(t, cc.Breaking)
+	assert.Eq(t, "the desc", cc.BreakingChange())
+
+	cc, err = gitutil.ParseConventional("refactor!: drop go1.19 support")
+	assert.NoErr(t, err)
+	assert.Eq(t, "drop go1.19 support", cc.BreakingChange())
+	assert.True(t, gitutil.IsConventional("docs: update readme\n"))
+}
+
+func TestParseConventional_error(t *testing.T) {
+	tests := []struct {
+		msg  string
+		line int
+		col  int
+	}{
+		{"", 1, 1},
+		{"update some files", 1, 7},
+		{":bug: (api): fix bug", 1, 7},
+		{"fix(api: fix bug", 1, 4},
+		{"fix(): fix bug", 1, 5},
+		{"✨ feat:add api", 1, 8},
+		{"feat(api): ", 1, 11},
+		{"feat: add api\nsome body", 2, 1},
+	}
+
+	for _, tt := range tests {
+		_, err := gitutil.ParseConventional(tt.msg)
+		var pe *gitutil.ParseError
+		if assert.True(t, errors.As(err, &pe), tt.msg) {
+			assert.Eq(t, tt.line, pe.Line, tt.msg)
+			assert.Eq(t, tt.col, pe.Column, tt.msg)
+		}
+	}
+}
+
+func TestParseCommitTopic(t *testing.T) {
+	assert.Eq(t, []string{"feat", "api", "cli"}, gitutil.ParseCommitTopic(":sparkles: feat(api,cli): some message"))
+	assert.Eq(t, []string{"fix"}, gitutil.ParseCommitTopic("fix: some bug"))
+	assert.Nil(t, gitutil.ParseCommitTopic("some message"))
+}
@@ -25,9 +25,16 @@ func IsRepoPath(path string) bool {
 	return repoPathReg.MatchString(path)
 }
 
-// ParseCommitTopic for git commit message
+// ParseCommitTopic parse topics of the conventional commit message, contains the type and scopes.
+// returns nil on the message is not a conventional commit.
+//
+// eg: "feat(api,cli): some message" => [feat, api, cli]
 func ParseCommitTopic(msg string) []string {
-	return nil // TODO
+	cc, err := ParseConventional(msg)
+	if err != nil {
+		return nil
+	}
+	return append([]string{cc.Type}, cc.Scopes...)
 }
 
 // ResolveGhURL string
@@ -0,0 +1,312 @@
+package gitutil
+
+import (
+	"strings"
+)
+
+// Trailer a key-value trailer(footer) of the commit message.
+//
+// eg: "Signed-off-by: inhere <in.798@qq.com>", "Fixes #123", "BREAKING CHANGE: some desc"
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// String to trailer line. eg: "Signed-off-by: inhere <in.798@qq.com>"
+func (t Trailer) String() string {
+	return t.Key + ": " + t.Value
+}
+
+// IsKey check the trailer key, is case-insensitive
+func (t Trailer) IsKey(key string) bool {
+	return strings.EqualFold(t.Key, key)
+}
+
+// some common trailer keys
+const (
+	TrailerSignedOffBy    = "Signed-off-by"
+	TrailerCoAuthoredBy   = "Co-authored-by"
+	TrailerReviewedBy     = "Reviewed-by"
+	TrailerFixes          = "Fixes"
+	TrailerBreakingChange = "BREAKING CHANGE"
+)
+
+// the trailers generated by git, a trailer block contains it only need 25% trailer lines.
+var gitTrailerPrefixes = []string{"Signed-off-by: ", "(cherry picked from commit "}
+
+// TrailerOpts options for parse and write the trailers, like the git config trailer.*
+type TrailerOpts struct {
+	// Separators the chars can be used between key and value, same as git config trailer.separators.
+	// default is ":". eg: ":#" will allow "Fixes #123"
+	//
+	// NOTE: the value of the "#" separator will keep the "#". eg: "Fixes #123" => "#123"
+	Separators string
+	// CommentChar the trailing lines start with it are comments, will be skipped and kept on write.
+	// default is empty, not strip the comment lines. same as git config core.commentChar
+	CommentChar string
+}
+
+// default options, use by the ParseTrailers, AddTrailer and more functions.
+var defaultTrailerOpts = &TrailerOpts{Separators: ":"}
+
+func (o *TrailerOpts) separators() string {
+	if o.Separators == "" {
+		return ":"
+	}
+	return o.Separators
+}
+
+// parse a trailer line. format: "<key><sep><value>", allow whitespaces before the separator.
+//
+// eg: "Signed-off-by: inhere", "Fixes #123"(with "#" separator), "BREAKING CHANGE: desc"
+func (o *TrailerOpts) parseTrailerLine(line string) (Trailer, bool) {
+	for _, key := range []string{TrailerBreakingChange, "BREAKING-CHANGE"} {
+		if strings.HasPrefix(line, key+":") {
+			return Trailer{Key: key, Value: strings.TrimSpace(line[len(key)+1:])}, true
+		}
+	}
+
+	// token: [A-Za-z0-9-]+
+	i := 0
+	for i < len(line) && isTokenChar(line[i]) {
+		i++
+	}
+	if i == 0 {
+		return Trailer{}, false
+	}
+
+	key, rest := line[:i], strings.TrimLeft(line[i:], " \t")
+	if rest == "" || !strings.ContainsRune(o.separators(), rune(rest[0])) {
+		return Trailer{}, false
+	}
+
+	// eg: "Fixes #123"
+	if rest[0] == '#' {
+		return Trailer{Key: key, Value: strings.TrimSpace(rest)}, true
+	}
+	return Trailer{Key: key, Value: strings.TrimSpace(rest[1:])}, true
+}
+
+func (o *TrailerOpts) isComment(line string) bool {
+	return o.CommentChar != "" && strings.HasPrefix(line, o.CommentChar)
+}
+
+func isTokenChar(c byte) bool {
+	return c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// message lines split by the trailer block
+type msgParts struct {
+	// lines before the trailer block, contains the blank line
+	head []string
+	// lines of the trailer block
+	block []string
+	// trailing comment and blank lines. eg: "# Please enter the commit message..."
+	tail []string
+}
+
+// split message to parts. the trailer block is the last paragraph, and it is not the first paragraph(title).
+func (o *TrailerOpts) splitMessage(msg string) *msgParts {
+	lines := strings.Split(strings.ReplaceAll(msg, "\r\n", "\n"), "\n")
+
+	// trailing comment and blank lines
+	end := len(lines)
+	for end > 0 && (strings.TrimSpace(lines[end-1]) == "" || o.isComment(lines[end-1])) {
+		end--
+	}
+
+	mp := &msgParts{tail: lines[end:]}
+	// cap to end: append to block will not overwrite the tail
+	lines = lines[:end:end]
+
+	// find the last paragraph
+	start := end
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+
+	// no paragraph before it, is the title.
+	if start == 0 || !o.isTrailerBlock(lines[start:]) {
+		mp.head = lines
+		return mp
+	}
+
+	mp.head, mp.block = lines[:start], lines[start:]
+	return mp
+}
+
+// check is trailer block by the rules of git interpret-trailers:
+// all lines are trailers, or at least 25% lines are trailers and contains a trailer generated by git.
+func (o *TrailerOpts) isTrailerBlock(lines []string) bool {
+	var trailers, others int
+	var hasGitTrailer bool
+
+	for _, line := range lines {
+		// continuation line of the trailer value
+		if trailers > 0 && (line[0] == ' ' || line[0] == '\t') {
+			continue
+		}
+
+		if _, ok := o.parseTrailerLine(line); ok {
+			trailers++
+		} else {
+			others++
+		}
+
+		for _, prefix := range gitTrailerPrefixes {
+			if strings.HasPrefix(line, prefix) {
+				hasGitTrailer = true
+			}
+		}
+	}
+
+	if trailers > 0 && others == 0 {
+		return true
+	}
+	return hasGitTrailer && trailers*3 >= others
+}
+
+// parse the trailer block lines, will unfold the continuation lines.
+func (o *TrailerOpts) parseBlock(lines []string) []Trailer {
+	var ts []Trailer
+	for _, line := range lines {
+		if len(ts) > 0 && (line[0] == ' ' || line[0] == '\t') {
+			ts[len(ts)-1].Value += " " + strings.TrimSpace(line)
+			continue
+		}
+
+		if t, ok := o.parseTrailerLine(line); ok {
+			ts = append(ts, t)
+		}
+	}
+	return ts
+}
+
+// ParseTrailers parse trailers from the full commit message(contains the title). see TrailerOpts.Parse()
+//
+// Usage:
+//
+//	ts := gitutil.ParseTrailers("fix: some bug\n\nCloses: #123\nSigned-off-by: inhere <in.798@qq.com>")
+func ParseTrailers(msg string) []Trailer {
+	return defaultTrailerOpts.Parse(msg)
+}
+
+// SplitTrailers split the commit message to body(without trailer block) and trailers.
+func SplitTrailers(msg string) (body string, trailers []Trailer) {
+	return defaultTrailerOpts.Split(msg)
+}
+
+// AddTrailer append a trailer to the commit message.
+// if the same trailer(key is case-insensitive) exists, will not add it.
+//
+// Usage:
+//
+//	msg = gitutil.AddTrailer(msg, gitutil.TrailerCoAuthoredBy, "inhere <in.798@qq.com>")
+func AddTrailer(msg, key, value string) string {
+	return defaultTrailerOpts.Add(msg, key, value)
+}
+
+// SetTrailer set the trailer to the commit message.
+// if the trailer key(case-insensitive) exists, will replace all of them with the new one.
+func SetTrailer(msg, key, value string) string {
+	return defaultTrailerOpts.Set(msg, key, value)
+}
+
+// RemoveTrailer remove the trailers by key(case-insensitive) from the commit message.
+func RemoveTrailer(msg, key string) string {
+	return defaultTrailerOpts.Remove(msg, key)
+}
+
+// Parse trailers from the full commit message(contains the title).
+//
+// Usage:
+//
+//	opts := &gitutil.TrailerOpts{Separators: ":#", CommentChar: "#"}
+//	ts := opts.Parse("fix: some bug\n\nFixes #123\n\n# Please enter the commit message")
+func (o *TrailerOpts) Parse(msg string) []Trailer {
+	return o.parseBlock(o.splitMessage(msg).block)
+}
+
+// Split the commit message to body(without trailer block) and trailers.
+func (o *TrailerOpts) Split(msg string) (body string, trailers []Trailer) {
+	mp := o.splitMessage(msg)
+	body = strings.TrimRight(strings.Join(mp.head, "\n"), "\n")
+	return body, o.parseBlock(mp.block)
+}
+
+// Add append a trailer to the commit message, if the same trailer not exists. see AddTrailer()
+func (o *TrailerOpts) Add(msg, key, value string) string {
+	for _, t := range o.Parse(msg) {
+		if t.IsKey(key) && t.Value == value {
+			return msg
+		}
+	}
+	return o.writeTrailer(msg, Trailer{Key: key, Value: value}, false)
+}
+
+// Set the trailer to the commit message, will replace the exists. see SetTrailer()
+func (o *TrailerOpts) Set(msg, key, value string) string {
+	return o.writeTrailer(msg, Trailer{Key: key, Value: value}, true)
+}
+
+// Remove the trailers by key(case-insensitive) from the commit message.
+func (o *TrailerOpts) Remove(msg, key string) string {
+	mp := o.splitMessage(msg)
+	if len(mp.block) == 0 {
+		return msg
+	}
+
+	mp.block = o.filterBlock(mp.block, key)
+	return mp.join()
+}
+
+func (o *TrailerOpts) writeTrailer(msg string, t Trailer, replace bool) string {
+	mp := o.splitMessage(msg)
+	if replace {
+		mp.block = o.filterBlock(mp.block, t.Key)
+	}
+
+	mp.block = append(mp.block, t.String())
+	return mp.join()
+}
+
+// remove the trailer lines and continuation lines by key.
+func (o *TrailerOpts) filterBlock(lines []string, key string) []string {
+	var skip bool
+	block := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line[0] == ' ' || line[0] == '\t' {
+			if !skip {
+				block = append(block, line)
+			}
+			continue
+		}
+
+		t, ok := o.parseTrailerLine(line)
+		skip = ok && t.IsKey(key)
+		if !skip {
+			block = append(block, line)
+		}
+	}
+	return block
+}
+
+// join parts to message. will ensure a blank line between the body and trailer block.
+func (mp *msgParts) join() string {
+	head := strings.TrimRight(strings.Join(mp.head, "\n"), "\n")
+
+	var sb strings.Builder
+	sb.WriteString(head)
+	if len(mp.block) > 0 {
+		if head != "" {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(strings.Join(mp.block, "\n"))
+	}
+
+	if len(mp.tail) > 0 {
+		sb.WriteByte('\n')
+		sb.WriteString(strings.Join(mp.tail, "\n"))
+	}
+	return sb.String()
+}
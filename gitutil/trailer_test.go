@@ -0,0 +1,87 @@
+package gitutil_test
+
+import (
+	"testing"
+
+	"github.com/gookit/gitw/gitutil"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParseTrailers(t *testing.T) {
+	msg := `feat: add some feature
+
+the body contents. Some-key: not trailer
+
+Fixes #123
+Reviewed-by: inhere
+  <in.798@qq.com>
+Co-authored-by: tom <tom@example.com>
+BREAKING CHANGE: the config format is changed
+`
+	opts := &gitutil.TrailerOpts{Separators: ":#"}
+	ts := opts.Parse(msg)
+	assert.Eq(t, []gitutil.Trailer{
+		{Key: "Fixes", Value: "#123"},
+		{Key: "Reviewed-by", Value: "inhere <in.798@qq.com>"},
+		{Key: "Co-authored-by", Value: "tom <tom@example.com>"},
+		{Key: "BREAKING CHANGE", Value: "the config format is changed"},
+	}, ts)
+
+	body, ts := opts.Split(msg)
+	assert.Eq(t, "feat: add some feature\n\nthe body contents. Some-key: not trailer", body)
+	assert.Len(t, ts, 4)
+
+	// default separator is ":", "Fixes #123" is not trailer
+	assert.Empty(t, gitutil.ParseTrailers(msg))
+	ts = gitutil.ParseTrailers("fix: bug\n\nFixes #123\nSigned-off-by: inhere\nKey : value")
+	assert.Eq(t, []gitutil.Trailer{{Key: "Signed-off-by", Value: "inhere"}, {Key: "Key", Value: "value"}}, ts)
+
+	// not strip the comment lines on CommentChar is empty
+	body, ts = gitutil.SplitTrailers("fix: bug\n\nSigned-off-by: inhere\n#123")
+	assert.Eq(t, "fix: bug", body)
+	assert.Eq(t, []gitutil.Trailer{{Key: "Signed-off-by", Value: "inhere"}}, ts)
+	body, _ = gitutil.SplitTrailers("fix: bug\n\nsome desc\n#123")
+	assert.Eq(t, "fix: bug\n\nsome desc\n#123", body)
+	ts = (&gitutil.TrailerOpts{CommentChar: "#"}).Parse("fix: bug\n\nReviewed-by: inhere\n#123")
+	assert.Eq(t, []gitutil.Trailer{{Key: "Reviewed-by", Value: "inhere"}}, ts)
+
+	// title only
+	assert.Empty(t, gitutil.ParseTrailers("Signed-off-by: inhere"))
+	// not trailer block
+	assert.Empty(t, gitutil.ParseTrailers("fix: bug\n\nsome desc\nKey: value"))
+	// at least 25% trailers and contains git trailer
+	ts = gitutil.ParseTrailers("fix: bug\n\nsome desc\nmore desc\nSigned-off-by: inhere")
+	assert.Eq(t, []gitutil.Trailer{{Key: "Signed-off-by", Value: "inhere"}}, ts)
+}
+
+func TestAddTrailer(t *testing.T) {
+	msg := gitutil.AddTrailer("fix: bug", gitutil.TrailerSignedOffBy, "inhere <in.798@qq.com>")
+	assert.Eq(t, "fix: bug\n\nSigned-off-by: inhere <in.798@qq.com>", msg)
+
+	// add to exists block
+	msg = gitutil.AddTrailer(msg+"\n", gitutil.TrailerCoAuthoredBy, "tom <tom@example.com>")
+	assert.Eq(t, "fix: bug\n\nSigned-off-by: inhere <in.798@qq.com>\nCo-authored-by: tom <tom@example.com>\n", msg)
+
+	// exists, not add
+	assert.Eq(t, msg, gitutil.AddTrailer(msg, "signed-off-by", "inhere <in.798@qq.com>"))
+
+	// keep the comments
+	opts := &gitutil.TrailerOpts{CommentChar: "#"}
+	msg = opts.Add("fix: bug\n\nsome desc\n\n# Please enter the commit message\n", "Fixes", "#23")
+	assert.Eq(t, "fix: bug\n\nsome desc\n\nFixes: #23\n\n# Please enter the commit message\n", msg)
+
+	// the "#123" line is not comment on CommentChar is empty
+	msg = gitutil.AddTrailer("fix: bug\n\nsome desc\n#123", "Fixes", "#23")
+	assert.Eq(t, "fix: bug\n\nsome desc\n#123\n\nFixes: #23", msg)
+}
+
+func TestSetTrailer(t *testing.T) {
+	msg := "fix: bug\n\nFixes: #12\nReviewed-by: tom\n  <tom@example.com>\nReviewed-by: inhere"
+	msg = gitutil.SetTrailer(msg, gitutil.TrailerReviewedBy, "jerry")
+	assert.Eq(t, "fix: bug\n\nFixes: #12\nReviewed-by: jerry", msg)
+
+	msg = gitutil.RemoveTrailer(msg, "fixes")
+	assert.Eq(t, "fix: bug\n\nReviewed-by: jerry", msg)
+	msg = gitutil.RemoveTrailer(msg, gitutil.TrailerReviewedBy)
+	assert.Eq(t, "fix: bug", msg)
+}
@@ -2,15 +2,19 @@
 package gitw
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/gookit/goutil/errorx"
-	"github.com/gookit/goutil/fsutil"
 	"github.com/gookit/goutil/sysutil/cmdr"
 )
 
@@ -56,17 +60,41 @@ type GitWrap struct {
 	Bin string
 	// Args for run git. contains git command name.
 	Args []string
+	// GlobalFlags for git, will be added before the Config and Args. eg: "--no-pager"
+	GlobalFlags []string
+	// Configs one-off config overrides, each will be added as `-c key=value` before the Args.
+	Configs []string
+	// Env more environment variables for run git. format: KEY=VALUE
+	//
+	// eg: GIT_DIR, GIT_WORK_TREE, GIT_AUTHOR_NAME, GIT_SSH_COMMAND
+	Env []string
 	// Stdin more settings
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
 
-	// DryRun if True, not real execute command
+	// Ctx context for run git command. see WithContext()
+	Ctx context.Context
+	// Timeout for run git command. 0 is not limited. see WithTimeout()
+	Timeout time.Duration
+
+	// Runner for run git command. default is DefaultRunner
+	Runner Runner
+
+	// DryRun if True, the mutating commands will not be executed, and be recorded to the plan.
+	// The read-only commands(eg: log, status) are still executed. see IsReadOnlyCmd()
+	//
+	// The plan is created by New() and shared with the sub commands by Cmd(), Sub(), New().
 	DryRun bool
-	// BeforeExec command hook.
+	// BeforeExec command hook. it will be called before the hooks chain.
 	//
 	// Usage: gw.BeforeExec = gitw.PrintCmdline
 	BeforeExec func(gw *GitWrap)
+
+	// exec hooks chain. see Use(), OnBeforeExec(), OnAfterExec()
+	hooks []ExecHook
+	// recorded mutating commands on DryRun
+	plan *DryRunPlan
 }
 
 // New create instance with args
@@ -77,6 +105,8 @@ func New(args ...string) *GitWrap {
 		// Stdin:  os.Stdin, // not init stdin
 		Stdout: os.Stdout,
 		Stderr: os.Stderr,
+		// create once, share with the copies
+		plan: &DryRunPlan{},
 	}
 }
 
@@ -133,37 +163,66 @@ func (gw *GitWrap) String() string {
 	return gw.Cmdline()
 }
 
-// Cmdline to command line
+// Cmdline to command line. will contain the Env, GlobalFlags and Configs.
+//
+// eg: `LC_ALL=C git -c core.quotepath=false status`
 func (gw *GitWrap) Cmdline() string {
 	b := new(strings.Builder)
+	for _, kv := range gw.Env {
+		writeQuotedArg(b, kv)
+		b.WriteByte(' ')
+	}
 	b.WriteString(gw.Bin)
 
-	for _, a := range gw.Args {
+	for _, a := range gw.FullArgs() {
 		b.WriteByte(' ')
-		if strings.ContainsRune(a, '"') {
-			b.WriteString(fmt.Sprintf(`'%s'`, a))
-		} else if a == "" || strings.ContainsRune(a, '\'') || strings.ContainsRune(a, ' ') {
-			b.WriteString(fmt.Sprintf(`"%s"`, a))
-		} else {
-			b.WriteString(a)
-		}
+		writeQuotedArg(b, a)
 	}
 	return b.String()
 }
 
+func writeQuotedArg(b *strings.Builder, a string) {
+	if strings.ContainsRune(a, '"') {
+		b.WriteString(fmt.Sprintf(`'%s'`, a))
+	} else if a == "" || strings.ContainsRune(a, '\'') || strings.ContainsRune(a, ' ') {
+		b.WriteString(fmt.Sprintf(`"%s"`, a))
+	} else {
+		b.WriteString(a)
+	}
+}
+
+// FullArgs get all args for run git. contains GlobalFlags, Configs and Args
+func (gw *GitWrap) FullArgs() []string {
+	if len(gw.GlobalFlags) == 0 && len(gw.Configs) == 0 {
+		return gw.Args
+	}
+
+	args := make([]string, 0, len(gw.GlobalFlags)+2*len(gw.Configs)+len(gw.Args))
+	args = append(args, gw.GlobalFlags...)
+	for _, kv := range gw.Configs {
+		args = append(args, "-c", kv)
+	}
+	return append(args, gw.Args...)
+}
+
 // IsGitRepo return the work dir is a git repo.
 func (gw *GitWrap) IsGitRepo() bool {
-	return fsutil.IsDir(gw.GitDir())
+	_, err := gw.ResolveGitDir()
+	return err == nil
 }
 
-// GitDir return .git data dir
+// GitDir return .git data dir. see ResolveGitDir()
+//
+// returns "Workdir/.git" on resolve failed.
 func (gw *GitWrap) GitDir() string {
-	gitDir := GitDir
-	if gw.Workdir != "" {
-		gitDir = gw.Workdir + "/" + GitDir
+	if dirs, err := gw.ResolveGitDir(); err == nil {
+		return dirs.GitDir
 	}
 
-	return gitDir
+	if gw.Workdir != "" {
+		return gw.Workdir + "/" + GitDir
+	}
+	return GitDir
 }
 
 // -------------------------------------------------
@@ -176,15 +235,89 @@ func (gw *GitWrap) PrintCmdline() *GitWrap {
 	return gw
 }
 
-// WithDryRun on exec command
+// WithDryRun on exec command. see DryRun field
 func (gw *GitWrap) WithDryRun(dryRun bool) *GitWrap {
 	gw.DryRun = dryRun
+	if dryRun && gw.plan == nil {
+		gw.plan = &DryRunPlan{}
+	}
+	return gw
+}
+
+// DryRunPlan get the recorded mutating commands on DryRun.
+//
+// Usage:
+//
+//	gw := gitw.New().WithDryRun(true)
+//	gw.Tag("v1.0.0").MustRun()
+//	fmt.Println(gw.DryRunPlan().Cmdlines()) // ["git tag v1.0.0"]
+//
+// NOTE: returns nil on the GitWrap is not created by New() and WithDryRun() is not called.
+func (gw *GitWrap) DryRunPlan() *DryRunPlan {
+	return gw.plan
+}
+
+// WithContext set the context for run git command.
+// on the context is canceled, will kill the git process tree.
+func (gw *GitWrap) WithContext(ctx context.Context) *GitWrap {
+	gw.Ctx = ctx
+	return gw
+}
+
+// WithTimeout set the timeout for run git command.
+// on timeout, will kill the git process tree and return *TimeoutError
+func (gw *GitWrap) WithTimeout(timeout time.Duration) *GitWrap {
+	gw.Timeout = timeout
+	return gw
+}
+
+// WithEnv add an environment variable for run git.
+//
+// Usage:
+//
+//	gw.WithEnv("GIT_SSH_COMMAND", "ssh -i ~/.ssh/id_deploy")
+func (gw *GitWrap) WithEnv(key, value string) *GitWrap {
+	// clip: not share the underlying array with the copied GitWrap
+	gw.Env = append(slices.Clip(gw.Env), key+"="+value)
+	return gw
+}
+
+// WithEnvMap add environment variables for run git.
+func (gw *GitWrap) WithEnvMap(envMp map[string]string) *GitWrap {
+	keys := make([]string, 0, len(envMp))
+	for key := range envMp {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+	for _, key := range keys {
+		gw.WithEnv(key, envMp[key])
+	}
+	return gw
+}
+
+// WithLocale pin the locale(LC_ALL) for run git, make the git output messages are stable for parse.
+//
+// Usage:
+//
+//	gw.WithLocale("C")
+func (gw *GitWrap) WithLocale(locale string) *GitWrap {
+	return gw.WithEnv("LC_ALL", locale)
+}
+
+// WithConfigOverride add one-off config override, will run as: git -c key=value ...
+func (gw *GitWrap) WithConfigOverride(key, value string) *GitWrap {
+	gw.Configs = append(slices.Clip(gw.Configs), key+"="+value)
 	return gw
 }
 
-// OnBeforeExec add hook
-func (gw *GitWrap) OnBeforeExec(fn func(gw *GitWrap)) *GitWrap {
-	gw.BeforeExec = fn
+// WithGlobalFlags add global flags for git, will be added before the subcommand.
+//
+// Usage:
+//
+//	gw.WithGlobalFlags("--no-pager", "--literal-pathspecs")
+func (gw *GitWrap) WithGlobalFlags(flags ...string) *GitWrap {
+	gw.GlobalFlags = append(slices.Clip(gw.GlobalFlags), flags...)
 	return gw
 }
 
@@ -276,25 +409,73 @@ func (gw *GitWrap) ResetArgs() {
 
 // NewExecCmd create exec.Cmd from current cmd
 func (gw *GitWrap) NewExecCmd() *exec.Cmd {
-	c := exec.Command(gw.Bin, gw.Args...)
-	c.Dir = gw.Workdir
-	c.Stdin = gw.Stdin
-	c.Stdout = gw.Stdout
-	c.Stderr = gw.Stderr
+	return gw.NewExecCmdContext(gw.baseCtx())
+}
 
+// NewExecCmdContext create exec.Cmd with context from current cmd
+//
+// NOTE: if the Stdin is a terminal, will not run in a new process group, the caller can't
+// give back the terminal after run. only the git process will be killed on context canceled.
+func (gw *GitWrap) NewExecCmdContext(ctx context.Context) *exec.Cmd {
+	rc := gw.newRunCmd()
+	rc.Stdin = gw.Stdin
+	rc.Stdout = gw.Stdout
+	rc.Stderr = gw.Stderr
+
+	c, restore := newExecCmd(ctx, rc)
+	if restore != nil {
+		c.SysProcAttr, c.Cancel = nil, nil
+	}
 	return c
 }
 
-// Success run and return whether success
-func (gw *GitWrap) Success() bool {
-	if gw.BeforeExec != nil {
-		gw.BeforeExec(gw)
+// WithRunner set custom runner for run git command.
+func (gw *GitWrap) WithRunner(runner Runner) *GitWrap {
+	gw.Runner = runner
+	return gw
+}
+
+func (gw *GitWrap) runner() Runner {
+	if gw.Runner != nil {
+		return gw.Runner
+	}
+	return DefaultRunner
+}
+
+func (gw *GitWrap) newRunCmd() *RunCmd {
+	return &RunCmd{
+		Bin:  gw.Bin,
+		Args: gw.FullArgs(),
+		Dir:  gw.Workdir,
+		Env:  gw.Env,
 	}
-	if gw.DryRun {
-		return true
+}
+
+// context get, will apply the Timeout setting.
+func (gw *GitWrap) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if gw.Timeout > 0 {
+		return context.WithTimeout(ctx, gw.Timeout)
 	}
+	// keep the background context, not run in new process group.
+	return ctx, func() {}
+}
+
+// baseCtx get context for run command, default is context.Background()
+func (gw *GitWrap) baseCtx() context.Context {
+	if gw.Ctx != nil {
+		return gw.Ctx
+	}
+	return context.Background()
+}
 
-	return gw.NewExecCmd().Run() == nil
+// Success run and return whether success
+func (gw *GitWrap) Success() bool {
+	return gw.SuccessContext(gw.baseCtx())
+}
+
+// SuccessContext run with context and return whether success
+func (gw *GitWrap) SuccessContext(ctx context.Context) bool {
+	return gw.run(ctx, gw.Stdout, gw.Stderr) == nil
 }
 
 // SafeLines run and return output as lines
@@ -312,9 +493,9 @@ func (gw *GitWrap) OutputLines() ([]string, error) {
 	return cmdr.OutputLines(out), err
 }
 
-// SafeOutput run and return output
+// SafeOutput run and return output. will not print stderr and ignore error.
 func (gw *GitWrap) SafeOutput() string {
-	gw.Stderr = nil // ignore stderr
+	gw.Stderr = nil // not print stderr, it is still captured into GitError
 	out, err := gw.Output()
 
 	if err != nil {
@@ -325,35 +506,26 @@ func (gw *GitWrap) SafeOutput() string {
 
 // Output run and return output
 func (gw *GitWrap) Output() (string, error) {
-	c := exec.Command(gw.Bin, gw.Args...)
-	c.Dir = gw.Workdir
-	c.Stderr = gw.Stderr
-
-	if gw.BeforeExec != nil {
-		gw.BeforeExec(gw)
-	}
-	if gw.DryRun {
-		return "DIY-RUN: OK", nil
-	}
+	return gw.OutputContext(gw.baseCtx())
+}
 
-	output, err := c.Output()
-	return string(output), err
+// OutputContext run with context and return output
+func (gw *GitWrap) OutputContext(ctx context.Context) (string, error) {
+	buf := new(bytes.Buffer)
+	err := gw.run(ctx, buf, gw.Stderr)
+	return buf.String(), err
 }
 
 // CombinedOutput run and return output, will combine stderr and stdout output
 func (gw *GitWrap) CombinedOutput() (string, error) {
-	c := exec.Command(gw.Bin, gw.Args...)
-	c.Dir = gw.Workdir
-
-	if gw.BeforeExec != nil {
-		gw.BeforeExec(gw)
-	}
-	if gw.DryRun {
-		return "DIY-RUN: OK", nil
-	}
+	return gw.CombinedOutputContext(gw.baseCtx())
+}
 
-	output, err := c.CombinedOutput()
-	return string(output), err
+// CombinedOutputContext run with context and return output, will combine stderr and stdout output
+func (gw *GitWrap) CombinedOutputContext(ctx context.Context) (string, error) {
+	buf := new(lockedBuffer)
+	err := gw.run(ctx, buf, buf)
+	return buf.String(), err
 }
 
 // MustRun a command. will panic on error
@@ -366,15 +538,7 @@ func (gw *GitWrap) MustRun() {
 // Run runs command with `Exec` on platforms except Windows
 // which only supports `Spawn`
 func (gw *GitWrap) Run() error {
-	if gw.BeforeExec != nil {
-		gw.BeforeExec(gw)
-	}
-	if gw.DryRun {
-		fmt.Println("DIY-RUN: OK")
-		return nil
-	}
-
-	return gw.NewExecCmd().Run()
+	return gw.RunContext(gw.baseCtx())
 
 	// if envutil.IsWindows() {
 	// 	return gw.Spawn()
@@ -382,12 +546,80 @@ func (gw *GitWrap) Run() error {
 	// return gw.Exec()
 }
 
-// Spawn runs command with spawn(3)
-func (gw *GitWrap) Spawn() error {
-	if gw.DryRun {
+// RunContext run command with context. on the context is done, will kill the git process tree.
+func (gw *GitWrap) RunContext(ctx context.Context) error {
+	return gw.run(ctx, gw.Stdout, gw.Stderr)
+}
+
+// run the command with exec hooks. on DryRun, the mutating command is not executed and no output.
+func (gw *GitWrap) run(ctx context.Context, stdout, stderr io.Writer) error {
+	res := gw.beforeRun()
+	if res.DryRun {
+		gw.afterRun(res)
 		return nil
 	}
-	return gw.NewExecCmd().Run()
+	return gw.doRun(ctx, res, stdout, stderr)
+}
+
+// doRun exec the command and fire after hooks.
+func (gw *GitWrap) doRun(ctx context.Context, res *ExecResult, stdout, stderr io.Writer) error {
+	start := time.Now()
+	cw := &countWriter{w: stdout}
+
+	res.ExitCode, res.Err = gw.exec(ctx, cw, stderr)
+	res.Duration = time.Since(start)
+	res.OutputSize = cw.n
+
+	gw.afterRun(res)
+	return res.Err
+}
+
+// exec run the command by Runner, will capture the stderr for GitError.
+func (gw *GitWrap) exec(ctx context.Context, stdout, stderr io.Writer) (int, error) {
+	ctx, cancel := gw.context(ctx)
+	defer cancel()
+
+	rc := gw.newRunCmd()
+	rc.Stdin = gw.Stdin
+	rc.Stdout = stdout
+
+	errBuf := &stderrBuffer{}
+	if stderr != nil {
+		rc.Stderr = io.MultiWriter(stderr, errBuf)
+	} else {
+		rc.Stderr = errBuf
+	}
+
+	code, err := gw.runner().Run(ctx, rc)
+	if err == nil && code != 0 {
+		err = errorx.Rawf("exit status %d", code)
+	}
+	return code, gw.newGitError(gw.ctxError(ctx, err), code, errBuf.String())
+}
+
+// lockedBuffer a bytes.Buffer can be written by stdout and stderr concurrently.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements io.Writer
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// String get contents
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Spawn runs command with spawn(3)
+func (gw *GitWrap) Spawn() error {
+	return gw.run(gw.baseCtx(), gw.Stdout, gw.Stderr)
 }
 
 // Exec runs command with exec(3)
@@ -402,17 +634,19 @@ func (gw *GitWrap) Exec() error {
 	}
 
 	args := []string{binary}
-	args = append(args, gw.Args...)
+	args = append(args, gw.FullArgs()...)
 
-	if gw.BeforeExec != nil {
-		gw.BeforeExec(gw)
-	}
-	if gw.DryRun {
-		fmt.Println("DIY-RUN: OK")
+	res := gw.beforeRun()
+	if res.DryRun {
+		gw.afterRun(res)
 		return nil
 	}
 
-	return syscall.Exec(binary, args, os.Environ())
+	// on success, the current process is replaced, after hooks will not be called.
+	res.Err = syscall.Exec(binary, args, append(os.Environ(), gw.Env...))
+	res.ExitCode = -1
+	gw.afterRun(res)
+	return res.Err
 }
 
 // -------------------------------------------------
@@ -0,0 +1,300 @@
+package gitw_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/gitw/gitwtest"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestGitWrap_WithTimeout(t *testing.T) {
+	gw := gitw.New("5").WithTimeout(100 * time.Millisecond)
+	gw.Bin = "sleep"
+
+	start := time.Now()
+	err := gw.Run()
+	assert.Err(t, err)
+	assert.True(t, errors.Is(err, gitw.ErrTimeout))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Lt(t, int64(time.Since(start)), int64(3*time.Second))
+
+	var te *gitw.TimeoutError
+	assert.True(t, errors.As(err, &te))
+	assert.Eq(t, "sleep 5", te.Cmdline)
+	// can reach the raw error
+	var ee *exec.ExitError
+	assert.True(t, errors.As(err, &ee))
+
+	_, err = gw.Output()
+	assert.True(t, errors.Is(err, gitw.ErrTimeout))
+}
+
+func TestGitWrap_RunContext(t *testing.T) {
+	gw := gitw.New("5")
+	gw.Bin = "sleep"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	err := gw.RunContext(ctx)
+	assert.Err(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, errors.Is(err, gitw.ErrTimeout))
+
+	out, err := gitw.New("version").WithContext(context.Background()).Output()
+	assert.NoErr(t, err)
+	assert.StrContains(t, out, "git version")
+}
+
+func TestGitWrap_Output_gitError(t *testing.T) {
+	dir := t.TempDir()
+	gw := gitw.NewWithWorkdir(dir, "log", "-1")
+	gw.Stderr = nil
+
+	_, err := gw.Output()
+	assert.Err(t, err)
+	assert.True(t, errors.Is(err, gitw.ErrNotRepo))
+	assert.False(t, errors.Is(err, gitw.ErrNetwork))
+
+	var ge *gitw.GitError
+	assert.True(t, errors.As(err, &ge))
+	assert.Eq(t, "git log -1", ge.Cmdline)
+	assert.Eq(t, dir, ge.Workdir)
+	assert.Eq(t, 128, ge.ExitCode)
+	assert.Eq(t, gitw.ErrKindNotRepo, ge.Kind)
+	assert.StrContains(t, ge.Stderr, "not a git repository")
+	assert.StrContains(t, ge.Message(), "fatal:")
+
+	// SafeOutput not print stderr
+	repo := gitw.NewRepo(dir)
+	assert.Empty(t, repo.LastCommitID())
+	assert.True(t, errors.Is(repo.Err(), gitw.ErrNotRepo))
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   gitw.ErrKind
+	}{
+		{"", gitw.ErrKindUnknown},
+		{"fatal: not a git repository (or any of the parent directories): .git", gitw.ErrKindNotRepo},
+		{"fatal: ambiguous argument 'v9.9': unknown revision or path not in the working tree.", gitw.ErrKindUnknownRevision},
+		{"CONFLICT (content): Merge conflict in a.txt", gitw.ErrKindMergeConflict},
+		{"fatal: Authentication failed for 'https://github.com/gookit/gitw.git/'", gitw.ErrKindAuthFailed},
+		{"git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.", gitw.ErrKindAuthFailed},
+		{"fatal: Unable to create '/repo/.git/index.lock': File exists.", gitw.ErrKindLockExists},
+		{"fatal: unable to access 'https://github.com/x/y/': Could not resolve host: github.com", gitw.ErrKindNetwork},
+	}
+
+	for _, tt := range tests {
+		assert.Eq(t, tt.want, gitw.ClassifyError(tt.stderr), tt.stderr)
+	}
+	assert.Eq(t, "merge-conflict", gitw.ErrKindMergeConflict.String())
+}
+
+func TestGitWrap_WithEnv(t *testing.T) {
+	gw := gitw.New("var", "GIT_AUTHOR_IDENT").
+		WithEnv("GIT_AUTHOR_NAME", "inhere").
+		WithEnv("GIT_AUTHOR_EMAIL", "in@example.com").
+		WithConfigOverride("core.quotepath", "false").
+		WithGlobalFlags("--no-pager")
+
+	assert.Eq(t, []string{"--no-pager", "-c", "core.quotepath=false", "var", "GIT_AUTHOR_IDENT"}, gw.FullArgs())
+	assert.Eq(t, "GIT_AUTHOR_NAME=inhere GIT_AUTHOR_EMAIL=in@example.com git --no-pager -c core.quotepath=false var GIT_AUTHOR_IDENT", gw.Cmdline())
+
+	out, err := gw.Output()
+	assert.NoErr(t, err)
+	assert.StrContains(t, out, "inhere <in@example.com>")
+
+	// sub command will inherit, but not share the env slice
+	sub := gw.Cmd("config", "--get", "core.quotepath").WithEnv("LC_ALL", "C")
+	assert.Len(t, sub.Env, 3)
+	assert.Len(t, gw.Env, 2)
+
+	out, err = sub.Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, "false", strings.TrimSpace(out))
+}
+
+func TestGitWrap_Stream(t *testing.T) {
+	fr := gitwtest.NewFakeRunner().
+		On("log --format=%s", "first line\r\nsecond line\nthird line").
+		On("log -z --format=%B", "msg 1\nbody\x00msg 2\x00").
+		OnError("log --format=%H", "fatal: bad revision\n", 128)
+	gw := gitw.New().WithRunner(fr)
+
+	var lines []string
+	err := gw.Log("--format=%s").Stream(func(line []byte) error {
+		lines = append(lines, string(line))
+		return nil
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"first line", "second line", "third line"}, lines)
+
+	// stop on half
+	lines = lines[:0]
+	err = gw.Log("--format=%s").Stream(func(line []byte) error {
+		lines = append(lines, string(line))
+		return gitw.ErrStopStream
+	})
+	assert.NoErr(t, err)
+	assert.Len(t, lines, 1)
+
+	// split by NUL
+	lines = lines[:0]
+	err = gw.Log("-z", "--format=%B").StreamBy(0, func(line []byte) error {
+		lines = append(lines, string(line))
+		return nil
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"msg 1\nbody", "msg 2"}, lines)
+
+	// run error
+	gw.Stderr = nil
+	err = gw.Log("--format=%H").Stream(func(line []byte) error { return nil })
+	assert.ErrIs(t, err, gitw.ErrUnknownRevision)
+}
+
+func TestGitWrap_StdoutPipe(t *testing.T) {
+	rc, err := gitw.New("version").StdoutPipe()
+	assert.NoErr(t, err)
+
+	bs, err := io.ReadAll(rc)
+	assert.NoErr(t, err)
+	assert.NoErr(t, rc.Close())
+	assert.StrContains(t, string(bs), "git version")
+
+	// close before read to EOF, will kill the process
+	gw := gitw.New("-c", "while true; do echo yes; done")
+	gw.Bin = "sh"
+	rc, err = gw.StdoutPipe()
+	assert.NoErr(t, err)
+
+	buf := make([]byte, 8)
+	_, err = rc.Read(buf)
+	assert.NoErr(t, err)
+	assert.NoErr(t, rc.Close())
+}
+
+func TestGitWrap_hooks(t *testing.T) {
+	fr := gitwtest.NewFakeRunner().
+		On("tag -l", "v0.1.0\nv0.2.0\n").
+		OnError("log -1", "fatal: bad revision\n", 128)
+
+	var calls []string
+	var results []*gitw.ExecResult
+	gw := gitw.New().WithRunner(fr).
+		OnBeforeExec(func(gw *gitw.GitWrap) {
+			calls = append(calls, "before1: "+gw.Cmdline())
+		}).
+		Use(&gitw.HookFuncs{
+			Before: func(gw *gitw.GitWrap) { calls = append(calls, "before2") },
+			After: func(gw *gitw.GitWrap, res *gitw.ExecResult) {
+				calls = append(calls, "after2")
+			},
+		}).
+		OnAfterExec(func(gw *gitw.GitWrap, res *gitw.ExecResult) {
+			calls = append(calls, "after3")
+			results = append(results, res)
+		})
+	gw.BeforeExec = func(gw *gitw.GitWrap) { calls = append(calls, "before0") }
+	gw.Stderr = nil
+
+	_, err := gw.Tag("-l").Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"before0", "before1: git tag -l", "before2", "after3", "after2"}, calls)
+	assert.Len(t, results, 1)
+	assert.Eq(t, "git tag -l", results[0].Cmdline)
+	assert.Eq(t, int64(14), results[0].OutputSize)
+	assert.Eq(t, 0, results[0].ExitCode)
+	assert.Gt(t, int64(results[0].Duration), int64(0))
+
+	// on error
+	_, err = gw.Log("-1").Output()
+	assert.Err(t, err)
+	assert.Len(t, results, 2)
+	assert.Eq(t, 128, results[1].ExitCode)
+	assert.ErrIs(t, results[1].Err, gitw.ErrUnknownRevision)
+
+	// on dry run
+	assert.NoErr(t, gw.Cmd("push").WithDryRun(true).Run())
+	assert.Len(t, results, 3)
+	assert.True(t, results[2].DryRun)
+	assert.False(t, fr.Called("push"))
+}
+
+func TestIsReadOnlyCmd(t *testing.T) {
+	tests := []struct {
+		args string
+		want bool
+	}{
+		{"status --porcelain", true},
+		{"-c core.quotepath=false --no-pager log -1", true},
+		{"-C /path/to/repo rev-parse HEAD", true},
+		{"branch", true},
+		{"branch -v --all", true},
+		{"branch --show-current", true},
+		{"branch fea_new", false},
+		{"branch -D fea_new", false},
+		{"tag -l --sort=-version:refname", true},
+		{"tag -n5", true},
+		{"tag v1.0.0", false},
+		{"tag -a v1.0.0 -m release", false},
+		{"remote -v", true},
+		{"remote add upstream url", false},
+		{"config --get user.name", true},
+		{"config user.name", true},
+		{"config user.name inhere", false},
+		{"config --unset user.name", false},
+		{"stash list", true},
+		{"stash", false},
+		{"reflog", true},
+		{"reflog expire --all", false},
+		{"push origin main", false},
+		{"-c user.name=x commit -m msg", false},
+	}
+
+	for _, tt := range tests {
+		assert.Eq(t, tt.want, gitw.IsReadOnlyCmd(strings.Split(tt.args, " ")), tt.args)
+	}
+}
+
+func TestGitWrap_WithDryRun(t *testing.T) {
+	fr := gitwtest.NewFakeRunner().On("tag -l", "v0.1.0\n")
+	gw := gitw.New().WithRunner(fr).WithDryRun(true).WithWorkDir("/path/to/repo")
+	gw.Stdout = nil
+
+	// read-only: run for real
+	out, err := gw.Tag("-l").Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, "v0.1.0\n", out)
+
+	// mutating: record to plan
+	out, err = gw.Tag("v0.2.0").Output()
+	assert.NoErr(t, err)
+	assert.Empty(t, out)
+	assert.NoErr(t, gw.Push("origin", "v0.2.0").Run())
+	assert.False(t, fr.Called("tag v0.2.0"))
+	assert.False(t, fr.Called("push origin v0.2.0"))
+
+	plan := gw.DryRunPlan()
+	assert.Eq(t, []string{"git tag v0.2.0", "git push origin v0.2.0"}, plan.Cmdlines())
+	assert.Eq(t, "/path/to/repo", plan.Cmds()[0].Workdir)
+
+	plan.Reset()
+	assert.Eq(t, 0, plan.Len())
+
+	// set DryRun by field, the plan is shared with the copies
+	gw = gitw.New().WithRunner(fr)
+	gw.DryRun = true
+	assert.NoErr(t, gw.Tag("v0.3.0").Run())
+	assert.NoErr(t, gw.Sub("push", "origin", "v0.3.0").Run())
+	assert.Eq(t, []string{"git tag v0.3.0", "git push origin v0.3.0"}, gw.DryRunPlan().Cmdlines())
+}
@@ -0,0 +1,225 @@
+// Package gitwtest provide a fake gitw.Runner for testing the code built on gitw, without git installed.
+// And the NewTempRepo() helper for the tests need a real git repo.
+//
+// Usage:
+//
+//	fr := gitwtest.NewFakeRunner().
+//		On("branch --show-current", "main\n").
+//		On("log -1 --format=%H", "a1b2c3d4e5f6\n")
+//	repo := gitw.NewRepo("/path/to/repo").WithRunner(fr)
+package gitwtest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/goutil/errorx"
+)
+
+// Result the canned result for a git command
+type Result struct {
+	// Args the full args line, not contains bin name. eg: "branch -v --all"
+	Args     string `json:"args"`
+	Dir      string `json:"dir,omitempty"`
+	Stdout   string `json:"stdout,omitempty"`
+	Stderr   string `json:"stderr,omitempty"`
+	ExitCode int    `json:"exit_code,omitempty"`
+}
+
+// ArgsLine build the key for match result. eg: "branch -v --all"
+func ArgsLine(args []string) string {
+	return strings.Join(args, " ")
+}
+
+// FakeRunner a fake gitw.Runner, returns canned outputs by the args line.
+type FakeRunner struct {
+	mu sync.Mutex
+	// canned results. key is args line
+	results map[string]*Result
+	// executed args lines
+	calls []string
+	// Fallback runner on not found canned result. if is nil, will return error.
+	Fallback gitw.Runner
+}
+
+// NewFakeRunner instance
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{results: make(map[string]*Result)}
+}
+
+// On add canned stdout for the args line
+func (f *FakeRunner) On(argsLine, stdout string) *FakeRunner {
+	return f.AddResult(&Result{Args: argsLine, Stdout: stdout})
+}
+
+// OnError add canned error result for the args line
+func (f *FakeRunner) OnError(argsLine, stderr string, exitCode int) *FakeRunner {
+	return f.AddResult(&Result{Args: argsLine, Stderr: stderr, ExitCode: exitCode})
+}
+
+// AddResult add canned results
+func (f *FakeRunner) AddResult(rs ...*Result) *FakeRunner {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, r := range rs {
+		f.results[r.Args] = r
+	}
+	return f
+}
+
+// Run implements gitw.Runner
+func (f *FakeRunner) Run(ctx context.Context, c *gitw.RunCmd) (int, error) {
+	line := ArgsLine(c.Args)
+
+	f.mu.Lock()
+	f.calls = append(f.calls, line)
+	r, ok := f.results[line]
+	f.mu.Unlock()
+
+	if !ok {
+		if f.Fallback != nil {
+			return f.Fallback.Run(ctx, c)
+		}
+
+		msg := "gitwtest: no canned result for: git " + line
+		writeTo(c.Stderr, msg+"\n")
+		return -1, errorx.Raw(msg)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return -1, err
+	}
+
+	writeTo(c.Stdout, r.Stdout)
+	writeTo(c.Stderr, r.Stderr)
+	if r.ExitCode != 0 {
+		return r.ExitCode, errorx.Rawf("exit status %d", r.ExitCode)
+	}
+	return 0, nil
+}
+
+// Calls get executed args lines
+func (f *FakeRunner) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// Called check the args line has been executed
+func (f *FakeRunner) Called(argsLine string) bool {
+	for _, line := range f.Calls() {
+		if line == argsLine {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset the executed calls
+func (f *FakeRunner) Reset() {
+	f.mu.Lock()
+	f.calls = nil
+	f.mu.Unlock()
+}
+
+// LoadFile load recorded results from JSON file, returns FakeRunner for replay.
+func LoadFile(file string) (*FakeRunner, error) {
+	bs, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*Result
+	if err := json.Unmarshal(bs, &rs); err != nil {
+		return nil, err
+	}
+	return NewFakeRunner().AddResult(rs...), nil
+}
+
+// Recorder wrap a real runner, will record the results of the executed commands.
+//
+// Usage:
+//
+//	rec := gitwtest.NewRecorder(nil)
+//	repo := gitw.NewRepo("./").WithRunner(rec)
+//	repo.Info()
+//	err := rec.SaveFile("testdata/repo-info.json")
+//
+//	// replay in tests
+//	fr, err := gitwtest.LoadFile("testdata/repo-info.json")
+type Recorder struct {
+	mu sync.Mutex
+	rs []*Result
+	// Runner the real runner. default is gitw.DefaultRunner
+	Runner gitw.Runner
+}
+
+// NewRecorder instance. if runner is nil, will use gitw.DefaultRunner
+func NewRecorder(runner gitw.Runner) *Recorder {
+	if runner == nil {
+		runner = gitw.DefaultRunner
+	}
+	return &Recorder{Runner: runner}
+}
+
+// Run implements gitw.Runner
+func (r *Recorder) Run(ctx context.Context, c *gitw.RunCmd) (int, error) {
+	var outBuf, errBuf strings.Builder
+
+	rc := *c
+	rc.Stdout = teeWriter(c.Stdout, &outBuf)
+	rc.Stderr = teeWriter(c.Stderr, &errBuf)
+
+	code, err := r.Runner.Run(ctx, &rc)
+
+	r.mu.Lock()
+	r.rs = append(r.rs, &Result{
+		Args:     ArgsLine(c.Args),
+		Dir:      c.Dir,
+		Stdout:   outBuf.String(),
+		Stderr:   errBuf.String(),
+		ExitCode: code,
+	})
+	r.mu.Unlock()
+	return code, err
+}
+
+// Results get recorded results
+func (r *Recorder) Results() []*Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Result(nil), r.rs...)
+}
+
+// FakeRunner create FakeRunner by recorded results
+func (r *Recorder) FakeRunner() *FakeRunner {
+	return NewFakeRunner().AddResult(r.Results()...)
+}
+
+// SaveFile save recorded results to JSON file
+func (r *Recorder) SaveFile(file string) error {
+	bs, err := json.MarshalIndent(r.Results(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, bs, 0644)
+}
+
+func writeTo(w io.Writer, s string) {
+	if w != nil && s != "" {
+		_, _ = io.WriteString(w, s)
+	}
+}
+
+func teeWriter(w io.Writer, buf io.Writer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return io.MultiWriter(w, buf)
+}
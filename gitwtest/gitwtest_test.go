@@ -0,0 +1,73 @@
+package gitwtest_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/gitw/gitwtest"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestFakeRunner(t *testing.T) {
+	fr := gitwtest.NewFakeRunner().
+		On("tag -l", "v0.1.0\nv0.2.0\n").
+		OnError("log -1", "fatal: not a git repository (or any of the parent directories): .git\n", 128)
+
+	gw := gitw.New().WithRunner(fr)
+	tags, err := gw.Tag("-l").OutputLines()
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"v0.1.0", "v0.2.0"}, tags)
+
+	gw.Stderr = nil
+	_, err = gw.Log("-1").Output()
+	assert.True(t, errors.Is(err, gitw.ErrNotRepo))
+
+	var ge *gitw.GitError
+	assert.True(t, errors.As(err, &ge))
+	assert.Eq(t, 128, ge.ExitCode)
+
+	// not found
+	_, err = gw.Status().Output()
+	assert.Err(t, err)
+	assert.True(t, fr.Called("tag -l"))
+	assert.Eq(t, []string{"tag -l", "log -1", "status"}, fr.Calls())
+}
+
+func TestRecorder(t *testing.T) {
+	rec := gitwtest.NewRecorder(nil)
+	out, err := gitw.New("version").WithRunner(rec).Output()
+	assert.NoErr(t, err)
+	assert.NotEmpty(t, out)
+
+	rs := rec.Results()
+	assert.Len(t, rs, 1)
+	assert.Eq(t, "version", rs[0].Args)
+	assert.Eq(t, out, rs[0].Stdout)
+
+	file := filepath.Join(t.TempDir(), "records.json")
+	assert.NoErr(t, rec.SaveFile(file))
+
+	fr, err := gitwtest.LoadFile(file)
+	assert.NoErr(t, err)
+
+	replay, err := gitw.New("version").WithRunner(fr).Output()
+	assert.NoErr(t, err)
+	assert.Eq(t, out, replay)
+}
+
+func TestNewTempRepo(t *testing.T) {
+	r := gitwtest.NewTempRepo(t, "feat: first commit", "fix: second commit")
+	assert.Eq(t, "main", r.CurBranchName())
+
+	commits, err := r.Commits(nil)
+	assert.NoErr(t, err)
+	assert.Len(t, commits, 2)
+	assert.Eq(t, gitwtest.TestUserName, commits[0].Author.Name)
+	assert.Eq(t, gitwtest.TestCommitDate, commits[0].Committer.When.Format(time.RFC3339))
+
+	// can commit without setup the identity
+	assert.NoErr(t, r.Cmd("commit", "-q", "--allow-empty", "-m", "chore: more").Run())
+}
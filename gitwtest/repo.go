@@ -0,0 +1,52 @@
+package gitwtest
+
+import (
+	"testing"
+
+	"github.com/gookit/gitw"
+)
+
+// the identity and dates for the commits of the temp repo
+const (
+	TestUserName  = "inhere"
+	TestUserEmail = "in.798@qq.com"
+	// TestAuthorDate and TestCommitDate for the initial commits of NewTempRepo()
+	TestAuthorDate = "2024-01-02T15:04:05+08:00"
+	TestCommitDate = "2024-01-03T15:04:05+08:00"
+)
+
+// NewTempRepo create a git repo on branch main in a temp dir for testing,
+// and make an empty commit for each message. the initial commits have fixed dates.
+//
+// The user name, email and "commit.gpgsign=false" are set as "-c" overrides of the repo GitWrap,
+// so the later commands can commit without setup the identity.
+//
+// Usage:
+//
+//	r := gitwtest.NewTempRepo(t, "feat: first commit", "fix: second commit")
+//	err := r.Cmd("commit", "-q", "--allow-empty", "-m", "chore: more").Run()
+func NewTempRepo(t testing.TB, commits ...string) *gitw.Repo {
+	t.Helper()
+
+	r := gitw.NewRepo(t.TempDir())
+	gw := r.Git()
+	gw.Stdout, gw.Stderr = nil, nil
+	gw.WithConfigOverride("user.name", TestUserName).
+		WithConfigOverride("user.email", TestUserEmail).
+		WithConfigOverride("commit.gpgsign", "false")
+
+	mustRun(t, r.Cmd("init", "-q", "-b", "main"))
+	for _, msg := range commits {
+		mustRun(t, r.Cmd("commit", "-q", "--allow-empty", "-m", msg).
+			WithEnv("GIT_AUTHOR_DATE", TestAuthorDate).
+			WithEnv("GIT_COMMITTER_DATE", TestCommitDate))
+	}
+	return r
+}
+
+func mustRun(t testing.TB, gw *gitw.GitWrap) {
+	t.Helper()
+	if err := gw.Run(); err != nil {
+		t.Fatalf("run %q failed: %v", gw.Cmdline(), err)
+	}
+}
@@ -0,0 +1,153 @@
+package gitw
+
+import (
+	"io"
+	"slices"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// ExecResult info of the executed git command, for the after exec hooks.
+type ExecResult struct {
+	// Cmdline the executed command line
+	Cmdline string
+	// Workdir for run git
+	Workdir string
+	// DryRun mark the command is not really executed, it's recorded to the DryRunPlan.
+	DryRun bool
+	// Duration of run the command
+	Duration time.Duration
+	// ExitCode of the git process. -1 on the process not started or killed.
+	ExitCode int
+	// OutputSize the stdout output size in bytes.
+	OutputSize int64
+	// Err run error, is *GitError on run failed.
+	Err error
+}
+
+// ExecHook interface for run git command.
+//
+// The BeforeExec will be called by added order, the AfterExec will be called by reverse order.
+type ExecHook interface {
+	// BeforeExec call it before run the git command
+	BeforeExec(gw *GitWrap)
+	// AfterExec call it after run the git command, also be called on DryRun.
+	AfterExec(gw *GitWrap, res *ExecResult)
+}
+
+// HookFuncs a simple ExecHook implements by funcs, func can be nil.
+type HookFuncs struct {
+	Before func(gw *GitWrap)
+	After  func(gw *GitWrap, res *ExecResult)
+}
+
+// BeforeExec implements ExecHook
+func (h *HookFuncs) BeforeExec(gw *GitWrap) {
+	if h.Before != nil {
+		h.Before(gw)
+	}
+}
+
+// AfterExec implements ExecHook
+func (h *HookFuncs) AfterExec(gw *GitWrap, res *ExecResult) {
+	if h.After != nil {
+		h.After(gw, res)
+	}
+}
+
+// Use add exec hooks to the chain.
+//
+// Usage:
+//
+//	gw.Use(&gitw.HookFuncs{After: gitw.LogExecResult})
+func (gw *GitWrap) Use(hooks ...ExecHook) *GitWrap {
+	// clip: not share the underlying array with the copied GitWrap
+	gw.hooks = append(slices.Clip(gw.hooks), hooks...)
+	return gw
+}
+
+// OnBeforeExec add before exec hook to the chain.
+func (gw *GitWrap) OnBeforeExec(fn func(gw *GitWrap)) *GitWrap {
+	return gw.Use(&HookFuncs{Before: fn})
+}
+
+// OnAfterExec add after exec hook to the chain.
+func (gw *GitWrap) OnAfterExec(fn func(gw *GitWrap, res *ExecResult)) *GitWrap {
+	return gw.Use(&HookFuncs{After: fn})
+}
+
+// ResetHooks remove all exec hooks. not contains the BeforeExec field.
+func (gw *GitWrap) ResetHooks() *GitWrap {
+	gw.hooks = nil
+	return gw
+}
+
+// fire before hooks and create result for after hooks.
+// on DryRun, the mutating command will be recorded to the plan.
+func (gw *GitWrap) beforeRun() *ExecResult {
+	if gw.BeforeExec != nil {
+		gw.BeforeExec(gw)
+	}
+	for _, h := range gw.hooks {
+		h.BeforeExec(gw)
+	}
+
+	res := &ExecResult{
+		Cmdline: gw.Cmdline(),
+		Workdir: gw.Workdir,
+		DryRun:  gw.DryRun && !IsReadOnlyCmd(gw.FullArgs()),
+	}
+
+	if res.DryRun && gw.plan != nil {
+		gw.plan.Add(gw)
+	}
+	return res
+}
+
+// fire after hooks by reverse order.
+func (gw *GitWrap) afterRun(res *ExecResult) {
+	for i := len(gw.hooks) - 1; i >= 0; i-- {
+		gw.hooks[i].AfterExec(gw, res)
+	}
+}
+
+// LogExecResult an after exec hook func, log the exec result by slog.
+//
+// Usage:
+//
+//	gw.OnAfterExec(gitw.LogExecResult)
+func LogExecResult(_ *GitWrap, res *ExecResult) {
+	data := slog.M{
+		"cmdline":  res.Cmdline,
+		"workdir":  res.Workdir,
+		"dry_run":  res.DryRun,
+		"duration": res.Duration.String(),
+		"exit":     res.ExitCode,
+		"out_size": res.OutputSize,
+	}
+
+	if res.Err != nil {
+		slog.WithData(data).Error("git command failed: ", res.Err)
+	} else {
+		slog.WithData(data).Debug("git command executed")
+	}
+}
+
+// countWriter count the written bytes
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer
+func (cw *countWriter) Write(p []byte) (n int, err error) {
+	if cw.w != nil {
+		n, err = cw.w.Write(p)
+	} else {
+		n = len(p)
+	}
+
+	cw.n += int64(n)
+	return
+}
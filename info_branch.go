@@ -15,6 +15,8 @@ const RemotePfxOnBranch = "remotes/"
 type BranchInfo struct {
 	// Current active branch
 	Current bool
+	// Worktree the branch is checked out in another linked worktree. line prefix is "+"
+	Worktree bool
 	// Name The full branch name. eg: fea_xx, remotes/origin/fea_xx
 	Name string
 	// Short only branch name. local branch is equals Name. eg: fea_xx
@@ -27,6 +29,17 @@ type BranchInfo struct {
 	Alias string
 	// Remote name. local branch is empty. eg: origin
 	Remote string
+
+	// Upstream the upstream branch of local branch. eg: origin/main
+	//
+	// NOTE: the Upstream, Ahead, Behind, Gone only be filled by Repo.BranchTracks()
+	Upstream string
+	// Ahead commits count of the branch not in upstream
+	Ahead int
+	// Behind commits count of the upstream not in branch
+	Behind int
+	// Gone the upstream is configured, but the remote branch has been deleted.
+	Gone bool
 }
 
 // NewBranchInfo from branch line text
@@ -2,8 +2,10 @@ package gitw
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/gookit/goutil/errorx"
 	"github.com/gookit/goutil/strutil"
 )
 
@@ -14,20 +16,33 @@ var statusRegex = regexp.MustCompile(StatusPattern)
 
 // StatusInfo struct
 //
-// by run: git status -bs -u
+// by run: git status --porcelain=v2 --branch -z -u
 type StatusInfo struct {
-	// Branch current branch name.
+	// Branch current branch name. empty on Detached
 	Branch string
 	// UpRemote current upstream remote name.
 	UpRemote string
 	// UpBranch current upstream remote branch name.
 	UpBranch string
 
+	// Oid the current commit ID. empty on Initial
+	Oid string
+	// Upstream current upstream. eg: origin/main
+	Upstream string
+	// Ahead, Behind commits count to the upstream.
+	Ahead, Behind int
+	// Detached HEAD state
+	Detached bool
+	// Initial no commits on the repo.
+	Initial bool
+	// Files all changed files. only parsed from porcelain v2 output
+	Files []*StatusFile
+
 	fileNum int
 
 	// Deleted files
 	Deleted []string
-	// Renamed files, contains RM(rename and modify) files
+	// Renamed files, contains RM(rename and modify) files. eg: "old -> new"
 	Renamed []string
 	// Modified files
 	Modified []string
@@ -35,6 +50,94 @@ type StatusInfo struct {
 	Unstacked []string
 }
 
+// status codes of the StatusFile.Index and StatusFile.Worktree
+const (
+	StatusUnmodified  byte = '.'
+	StatusModified    byte = 'M'
+	StatusTypeChanged byte = 'T'
+	StatusAdded       byte = 'A'
+	StatusDeleted     byte = 'D'
+	StatusRenamed     byte = 'R'
+	StatusCopied      byte = 'C'
+	StatusUnmerged    byte = 'U'
+	StatusUntracked   byte = '?'
+	StatusIgnored     byte = '!'
+)
+
+// conflict types of the unmerged file. see StatusFile.ConflictType()
+var conflictTypes = map[string]string{
+	"DD": "both deleted",
+	"AU": "added by us",
+	"UD": "deleted by them",
+	"UA": "added by them",
+	"DU": "deleted by us",
+	"AA": "both added",
+	"UU": "both modified",
+}
+
+// SubmoduleState of the changed file. see git status --porcelain=v2
+type SubmoduleState struct {
+	// IsSubmodule the file is a submodule
+	IsSubmodule bool
+	// CommitChanged the submodule commit is changed
+	CommitChanged bool
+	// Modified the submodule has tracked changes
+	Modified bool
+	// Untracked the submodule has untracked files
+	Untracked bool
+}
+
+// StatusFile a changed file of the git status
+type StatusFile struct {
+	// Index status code of the staged changes. StatusUnmodified for no changes.
+	Index byte
+	// Worktree status code of the unstaged changes. StatusUnmodified for no changes.
+	Worktree byte
+	// Path of the file
+	Path string
+	// OrigPath the original path of the renamed or copied file.
+	OrigPath string
+	// Score the similarity score of the renamed or copied file. eg: 100
+	Score int
+	// Conflict XY codes of the unmerged file. eg: "UU"
+	Conflict string
+	// Submodule state
+	Submodule SubmoduleState
+}
+
+// Code get XY status code. eg: "M.", "R.", "??"
+func (sf *StatusFile) Code() string {
+	return string([]byte{sf.Index, sf.Worktree})
+}
+
+// IsStaged has staged changes
+func (sf *StatusFile) IsStaged() bool {
+	return sf.Conflict == "" && isChangedCode(sf.Index)
+}
+
+// IsUnstaged has unstaged changes
+func (sf *StatusFile) IsUnstaged() bool {
+	return sf.Conflict == "" && isChangedCode(sf.Worktree)
+}
+
+// IsUntracked file
+func (sf *StatusFile) IsUntracked() bool { return sf.Index == StatusUntracked }
+
+// IsIgnored file
+func (sf *StatusFile) IsIgnored() bool { return sf.Index == StatusIgnored }
+
+// IsConflict the file is unmerged
+func (sf *StatusFile) IsConflict() bool { return sf.Conflict != "" }
+
+// ConflictType get the conflict type description. eg: "both modified"
+func (sf *StatusFile) ConflictType() string {
+	return conflictTypes[sf.Conflict]
+}
+
+func isChangedCode(c byte) bool {
+	return c != StatusUnmodified && c != StatusUntracked && c != StatusIgnored && c != 0
+}
+
 // NewStatusInfo from string.
 func NewStatusInfo(str string) *StatusInfo {
 	si := &StatusInfo{}
@@ -46,7 +149,180 @@ func (si *StatusInfo) FromString(str string) *StatusInfo {
 	return si.FromLines(strings.Split(str, "\n"))
 }
 
-// FromLines parse and load info
+// ParseStatusV2 parse the output of: git status --porcelain=v2 --branch [-z]
+func ParseStatusV2(out string) (*StatusInfo, error) {
+	si := &StatusInfo{}
+	return si, si.FromPorcelainV2(out)
+}
+
+// FromPorcelainV2 parse and load info from the output of: git status --porcelain=v2 --branch [-z]
+//
+// see https://git-scm.com/docs/git-status#_porcelain_format_version_2
+func (si *StatusInfo) FromPorcelainV2(out string) error {
+	// with -z: each entry is terminated by NUL, the orig path of rename is the next entry.
+	sep := "\x00"
+	if !strings.Contains(out, sep) {
+		sep = "\n"
+	}
+
+	entries := strings.Split(out, sep)
+	for i := 0; i < len(entries); i++ {
+		line := strings.TrimSuffix(entries[i], "\r")
+		if len(line) < 2 {
+			continue
+		}
+
+		var sf *StatusFile
+		switch line[0] {
+		case '#':
+			si.parseV2Header(line)
+			continue
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			ss := strings.SplitN(line, " ", 9)
+			if len(ss) < 9 {
+				return errorx.Rawf("invalid status entry: %q", line)
+			}
+			sf = newStatusFile(ss[1], ss[2], ss[8])
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path><sep><origPath>
+			ss := strings.SplitN(line, " ", 10)
+			if len(ss) < 10 || len(ss[8]) < 1 {
+				return errorx.Rawf("invalid status entry: %q", line)
+			}
+
+			sf = newStatusFile(ss[1], ss[2], ss[9])
+			sf.Score, _ = strconv.Atoi(ss[8][1:])
+			if sep == "\n" {
+				sf.Path, sf.OrigPath = strutil.MustCut(sf.Path, "\t")
+			} else if i+1 < len(entries) {
+				i++
+				sf.OrigPath = entries[i]
+			}
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			ss := strings.SplitN(line, " ", 11)
+			if len(ss) < 11 {
+				return errorx.Rawf("invalid status entry: %q", line)
+			}
+			sf = newStatusFile(ss[1], ss[2], ss[10])
+			sf.Conflict = ss[1]
+		case '?', '!':
+			sf = &StatusFile{Index: line[0], Worktree: line[0], Path: line[2:]}
+		default:
+			return errorx.Rawf("invalid status entry: %q", line)
+		}
+
+		si.addFile(sf)
+	}
+	return nil
+}
+
+// # branch.oid <commit> | (initial)
+// # branch.head <branch> | (detached)
+// # branch.upstream <upstream_branch>
+// # branch.ab +<ahead> -<behind>
+func (si *StatusInfo) parseV2Header(line string) {
+	key, val := strutil.MustCut(line[2:], " ")
+	switch key {
+	case "branch.oid":
+		if val == "(initial)" {
+			si.Initial = true
+		} else {
+			si.Oid = val
+		}
+	case "branch.head":
+		if val == "(detached)" {
+			si.Detached = true
+		} else {
+			si.Branch = val
+		}
+	case "branch.upstream":
+		si.Upstream = val
+		si.UpRemote, si.UpBranch = strutil.QuietCut(val, "/")
+	case "branch.ab":
+		ahead, behind := strutil.MustCut(val, " ")
+		si.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+		si.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+	}
+}
+
+func newStatusFile(xy, sub, path string) *StatusFile {
+	sf := &StatusFile{Path: path}
+	if len(xy) == 2 {
+		sf.Index, sf.Worktree = xy[0], xy[1]
+	}
+
+	// N... or S<c><m><u>
+	if len(sub) == 4 && sub[0] == 'S' {
+		sf.Submodule = SubmoduleState{
+			IsSubmodule:   true,
+			CommitChanged: sub[1] == 'C',
+			Modified:      sub[2] == 'M',
+			Untracked:     sub[3] == 'U',
+		}
+	}
+	return sf
+}
+
+// add file and fill the compatible file lists
+func (si *StatusInfo) addFile(sf *StatusFile) {
+	si.Files = append(si.Files, sf)
+	if sf.IsIgnored() {
+		return
+	}
+
+	si.fileNum++
+	switch {
+	case sf.IsUntracked():
+		si.Unstacked = append(si.Unstacked, sf.Path)
+	case sf.IsConflict():
+		si.Modified = append(si.Modified, sf.Path)
+	case sf.Index == StatusRenamed || sf.Index == StatusCopied:
+		si.Renamed = append(si.Renamed, sf.OrigPath+" -> "+sf.Path)
+	case sf.Index == StatusDeleted || sf.Worktree == StatusDeleted:
+		si.Deleted = append(si.Deleted, sf.Path)
+	case sf.Index == StatusModified || sf.Worktree == StatusModified:
+		si.Modified = append(si.Modified, sf.Path)
+	}
+}
+
+// StagedFiles get files has staged changes
+func (si *StatusInfo) StagedFiles() []*StatusFile {
+	return si.filterFiles((*StatusFile).IsStaged)
+}
+
+// UnstagedFiles get files has unstaged changes
+func (si *StatusInfo) UnstagedFiles() []*StatusFile {
+	return si.filterFiles((*StatusFile).IsUnstaged)
+}
+
+// UntrackedFiles get untracked files
+func (si *StatusInfo) UntrackedFiles() []*StatusFile {
+	return si.filterFiles((*StatusFile).IsUntracked)
+}
+
+// ConflictFiles get unmerged files
+func (si *StatusInfo) ConflictFiles() []*StatusFile {
+	return si.filterFiles((*StatusFile).IsConflict)
+}
+
+// HasConflict check has unmerged files
+func (si *StatusInfo) HasConflict() bool {
+	return len(si.ConflictFiles()) > 0
+}
+
+func (si *StatusInfo) filterFiles(fn func(sf *StatusFile) bool) []*StatusFile {
+	var files []*StatusFile
+	for _, sf := range si.Files {
+		if fn(sf) {
+			files = append(files, sf)
+		}
+	}
+	return files
+}
+
+// FromLines parse and load info from the output of: git status -bs -u
 func (si *StatusInfo) FromLines(lines []string) *StatusInfo {
 	for _, line := range lines {
 		line = strings.Trim(line, " \t")
@@ -145,6 +145,13 @@ func TestParseBranchLine_verbose(t *testing.T) {
 	assert.Eq(t, "6fb8dcd", info.Hash)
 	assert.Eq(t, "the message 003", info.HashMsg)
 
+	// checked out in a linked worktree
+	info, err = gitw.ParseBranchLine("+ feat-x                     4d5e6f7 the message 004", true)
+	assert.NoErr(t, err)
+	assert.True(t, info.Worktree)
+	assert.Eq(t, "feat-x", info.Name)
+	assert.Eq(t, "4d5e6f7", info.Hash)
+
 	info, err = gitw.ParseBranchLine("* （头指针在 v0.2.3 分离） 3c08adf chore: update readme add branch info docs", true)
 	assert.Err(t, err)
 	info, err = gitw.ParseBranchLine("* (HEAD detached at pull/29/merge)                                    62f3455 Merge cfc79b748e176c1c9e266c8bc413c87fe974acef into c9503c2aef993a2cf582d90c137deda53c9bca68", true)
@@ -284,3 +291,69 @@ func TestStatusInfo_FromLines(t *testing.T) {
 	assert.False(t, si.IsCleaned())
 	assert.Gt(t, si.FileNum(), 2)
 }
+
+func TestParseStatusV2(t *testing.T) {
+	out := strings.Join([]string{
+		"# branch.oid 3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61",
+		"# branch.head main",
+		"# branch.upstream origin/fea/main",
+		"# branch.ab +2 -1",
+		"1 M. N... 100644 100644 100644 3a6d3bb 3a6d3bc repo.go",
+		"1 .D N... 100644 100644 000000 3a6d3bb 3a6d3bb tmp/delete some.file",
+		"1 A. N... 000000 100644 100644 0000000 3a6d3bb new.go",
+		"2 RM N... 100644 100644 100644 3a6d3bb 3a6d3bb R95 info_status.go",
+		"status.go",
+		"u UU N... 100644 100644 100644 100644 3a6d3b1 3a6d3b2 3a6d3b3 README.md",
+		"1 .M SC.U 160000 160000 160000 3a6d3bb 3a6d3bb vendor/lib",
+		"? untracked.txt",
+		"! ignored.log",
+		"",
+	}, "\x00")
+
+	si, err := gitw.ParseStatusV2(out)
+	assert.NoErr(t, err)
+	assert.Eq(t, "main", si.Branch)
+	assert.Eq(t, "origin/fea/main", si.Upstream)
+	assert.Eq(t, "origin", si.UpRemote)
+	assert.Eq(t, "fea/main", si.UpBranch)
+	assert.Eq(t, 2, si.Ahead)
+	assert.Eq(t, 1, si.Behind)
+	assert.False(t, si.Detached)
+	assert.Len(t, si.Files, 8)
+	assert.Eq(t, 7, si.FileNum())
+
+	sf := si.Files[3]
+	assert.Eq(t, "RM", sf.Code())
+	assert.Eq(t, "info_status.go", sf.Path)
+	assert.Eq(t, "status.go", sf.OrigPath)
+	assert.Eq(t, 95, sf.Score)
+	assert.True(t, sf.IsStaged())
+	assert.True(t, sf.IsUnstaged())
+
+	assert.Eq(t, "tmp/delete some.file", si.Files[1].Path)
+	assert.Len(t, si.StagedFiles(), 3)
+	assert.Len(t, si.UnstagedFiles(), 3)
+	assert.Len(t, si.UntrackedFiles(), 1)
+	assert.True(t, si.HasConflict())
+	assert.Eq(t, "both modified", si.ConflictFiles()[0].ConflictType())
+	assert.True(t, si.Files[5].Submodule.IsSubmodule)
+	assert.True(t, si.Files[5].Submodule.CommitChanged)
+	assert.True(t, si.Files[5].Submodule.Untracked)
+
+	// compatible lists
+	assert.Eq(t, []string{"status.go -> info_status.go"}, si.Renamed)
+	assert.Eq(t, []string{"tmp/delete some.file"}, si.Deleted)
+	assert.Eq(t, []string{"untracked.txt"}, si.Unstacked)
+
+	// detached and initial, without -z
+	si, err = gitw.ParseStatusV2("# branch.oid (initial)\n# branch.head (detached)\n2 R. N... 100644 100644 100644 3a6d3bb 3a6d3bb R100 b.go\ta.go\n")
+	assert.NoErr(t, err)
+	assert.True(t, si.Initial)
+	assert.True(t, si.Detached)
+	assert.Empty(t, si.Branch)
+	assert.Eq(t, "a.go", si.Files[0].OrigPath)
+	assert.Eq(t, "b.go", si.Files[0].Path)
+
+	_, err = gitw.ParseStatusV2("1 M. N... repo.go")
+	assert.Err(t, err)
+}
@@ -139,6 +139,10 @@ func ParseBranchLine(line string, verbose bool) (*BranchInfo, error) {
 		info.Current = true
 		line = strings.Trim(line, "*\t ")
 	}
+	if strings.HasPrefix(line, "+") {
+		info.Worktree = true
+		line = strings.Trim(line, "+\t ")
+	}
 
 	if line == "" {
 		return nil, ErrInvalidBrLine
@@ -173,6 +177,6 @@ func ParseBranchLine(line string, verbose bool) (*BranchInfo, error) {
 }
 
 func isVerboseBranchLine(line string) bool {
-	line = strings.Trim(line, " *\t\n\r\x0B")
+	line = strings.Trim(line, " *+\t\n\r\x0B")
 	return strings.ContainsRune(line, ' ')
 }
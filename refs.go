@@ -0,0 +1,442 @@
+package gitw
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/fsutil"
+)
+
+// prefixes of the ref names
+const (
+	RefsPrefix        = "refs/"
+	RefsHeadsPrefix   = "refs/heads/"
+	RefsTagsPrefix    = "refs/tags/"
+	RefsRemotesPrefix = "refs/remotes/"
+)
+
+// ErrRefNotFound error for the ref is not exists.
+//
+// Usage:
+//
+//	errors.Is(err, gitw.ErrRefNotFound)
+var ErrRefNotFound = errorx.Raw("git ref not found")
+
+// ErrRefsUnsupported error for the refs storage can not be read natively. eg: reftable
+var ErrRefsUnsupported = errorx.Raw("git refs storage is not supported")
+
+// max depth for resolve the symbolic refs, same as git.
+const maxSymrefDepth = 5
+
+// RefInfo of a git ref
+type RefInfo struct {
+	// Name full ref name. eg: refs/heads/main, HEAD
+	Name string
+	// Hash the commit ID or tag object ID. is empty on the symbolic ref
+	Hash string
+	// Peeled the commit ID of the annotated tag. is empty on not a tag or not peeled.
+	Peeled string
+	// Target ref name of the symbolic ref. eg: refs/remotes/origin/main
+	Target string
+}
+
+// IsSymbolic check the ref is symbolic ref
+func (ri *RefInfo) IsSymbolic() bool { return ri.Target != "" }
+
+// ShortName get the short name of the ref. eg: refs/heads/main => main, refs/remotes/origin/main => origin/main
+func (ri *RefInfo) ShortName() string {
+	return ShortRefName(ri.Name)
+}
+
+// ShortRefName get the short name of the full ref name. eg: refs/heads/main => main
+func ShortRefName(name string) string {
+	for _, prefix := range []string{RefsHeadsPrefix, RefsTagsPrefix, RefsRemotesPrefix} {
+		if s, ok := strings.CutPrefix(name, prefix); ok {
+			return s
+		}
+	}
+	return strings.TrimPrefix(name, RefsPrefix)
+}
+
+// ReflogEntry a line of the reflog file
+type ReflogEntry struct {
+	// Old commit ID. is zero ID on created the ref
+	Old string
+	// New commit ID
+	New string
+	// Committer of the change, When is the time of change
+	Committer Person
+	// Message of the change. eg: "commit: fix some bug", "checkout: moving from main to dev"
+	Message string
+}
+
+// RefReader read the refs, HEAD, packed-refs and reflogs from the git dir without spawn git process.
+//
+// NOTE: the reftable refs storage is not supported, will return ErrRefsUnsupported.
+// The RefReader is safe for concurrent use.
+//
+// Usage:
+//
+//	rr, err := gitw.NewRefReader(dirs)
+//	hash, err := rr.Resolve("HEAD")
+//	tags, err := rr.Refs(gitw.RefsTagsPrefix)
+type RefReader struct {
+	dirs *GitDirs
+
+	mu sync.Mutex
+	// cache the parsed packed-refs, reload on the file changed.
+	packedStamp fileStamp
+	packed      map[string]*RefInfo
+}
+
+// NewRefReader create a RefReader for the git dirs. see ResolveGitDir()
+func NewRefReader(dirs *GitDirs) (*RefReader, error) {
+	if fsutil.IsDir(filepath.Join(dirs.CommonDir, "reftable")) {
+		return nil, errorx.Wrapf(ErrRefsUnsupported, "the reftable is used in %s", dirs.CommonDir)
+	}
+	return &RefReader{dirs: dirs}, nil
+}
+
+// Head read the HEAD ref. Target is the branch ref on HEAD is symbolic, otherwise is detached.
+func (rr *RefReader) Head() (*RefInfo, error) {
+	return rr.readLoose(HeadFile)
+}
+
+// HeadBranch get current branch short name. returns "HEAD" on detached HEAD, same as:
+//
+//	git rev-parse --abbrev-ref HEAD
+func (rr *RefReader) HeadBranch() (string, error) {
+	head, err := rr.Head()
+	if err != nil {
+		return "", err
+	}
+
+	if branch, ok := strings.CutPrefix(head.Target, RefsHeadsPrefix); ok {
+		return branch, nil
+	}
+	return HeadFile, nil
+}
+
+// Ref read the ref by full name, not follow the symbolic ref. eg: refs/heads/main, HEAD
+//
+// the loose ref has priority over the packed ref.
+func (rr *RefReader) Ref(name string) (*RefInfo, error) {
+	ri, err := rr.readLoose(name)
+	if err == nil || !errors.Is(err, ErrRefNotFound) {
+		return ri, err
+	}
+
+	packed, err := rr.loadPacked()
+	if err != nil {
+		return nil, err
+	}
+
+	if ri, ok := packed[name]; ok {
+		return ri, nil
+	}
+	return nil, errorx.Wrapf(ErrRefNotFound, "ref %q not found", name)
+}
+
+// Resolve the ref to the commit ID, will follow the symbolic refs.
+//
+// name can be full ref name or short name, will lookup like git rev-parse. eg: HEAD, main, v1.0.0, origin/main
+func (rr *RefReader) Resolve(name string) (string, error) {
+	ri, err := rr.lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	for depth := 0; ri.IsSymbolic(); depth++ {
+		if depth >= maxSymrefDepth {
+			return "", errorx.Rawf("symbolic ref %q is too deep", name)
+		}
+
+		if ri, err = rr.Ref(ri.Target); err != nil {
+			return "", err
+		}
+	}
+	return ri.Hash, nil
+}
+
+// the lookup rules of the short ref name. see gitrevisions(7)
+var refLookupRules = []string{"%s", RefsPrefix + "%s", RefsTagsPrefix + "%s", RefsHeadsPrefix + "%s", RefsRemotesPrefix + "%s", RefsRemotesPrefix + "%s/HEAD"}
+
+func (rr *RefReader) lookup(name string) (*RefInfo, error) {
+	if name == "" || strings.Contains(name, "..") {
+		return nil, errorx.Rawf("invalid ref name %q", name)
+	}
+
+	for i, rule := range refLookupRules {
+		full := strings.Replace(rule, "%s", name, 1)
+		// only the special refs in the top level. eg: HEAD, FETCH_HEAD, ORIG_HEAD
+		if i == 0 && !strings.HasPrefix(name, RefsPrefix) && strings.ToUpper(name) != name {
+			continue
+		}
+
+		ri, err := rr.Ref(full)
+		if err == nil {
+			return ri, nil
+		}
+		if !errors.Is(err, ErrRefNotFound) {
+			return nil, err
+		}
+	}
+	return nil, errorx.Wrapf(ErrRefNotFound, "ref %q not found", name)
+}
+
+// Exists check the ref is exists by full name. eg: refs/heads/main
+func (rr *RefReader) Exists(name string) bool {
+	_, err := rr.Ref(name)
+	return err == nil
+}
+
+// Refs list the refs by prefix, sorted by name. eg: prefix "refs/tags/" for all tags
+//
+// prefix is empty for all refs under "refs/"
+func (rr *RefReader) Refs(prefix string) ([]*RefInfo, error) {
+	packed, err := rr.loadPacked()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string]*RefInfo, len(packed))
+	for name, ri := range packed {
+		if strings.HasPrefix(name, prefix) {
+			refs[name] = ri
+		}
+	}
+
+	// loose refs, walk in the common dir and the private refs of the worktree
+	dirs := []string{filepath.Join(rr.dirs.CommonDir, "refs")}
+	if rr.dirs.IsLinkedWorktree() {
+		dirs = append(dirs, filepath.Join(rr.dirs.GitDir, "refs"))
+	}
+
+	for i, root := range dirs {
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() || strings.HasSuffix(path, ".lock") {
+				return nil
+			}
+
+			rel, _ := filepath.Rel(filepath.Dir(root), path)
+			name := filepath.ToSlash(rel)
+			// the worktree private refs: refs/bisect, refs/worktree, refs/rewritten
+			private := rr.dirs.IsLinkedWorktree() && !isCommonPath(name)
+			if private != (i == 1) || !strings.HasPrefix(name, prefix) {
+				return nil
+			}
+
+			ri, err := rr.readLoose(name)
+			if err != nil {
+				return err
+			}
+			refs[name] = ri
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	list := make([]*RefInfo, 0, len(refs))
+	for _, ri := range refs {
+		list = append(list, ri)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// Reflog read the reflog entries of the ref, the first one is the latest. eg: HEAD, refs/heads/main
+//
+// returns empty on the reflog is not exists.
+func (rr *RefReader) Reflog(name string) ([]*ReflogEntry, error) {
+	bs, err := os.ReadFile(rr.dirs.Path("logs", name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(bs), "\n"), "\n")
+	entries := make([]*ReflogEntry, 0, len(lines))
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i] == "" {
+			continue
+		}
+
+		ent, err := ParseReflogLine(lines[i])
+		if err != nil {
+			return nil, errorx.Wrapf(err, "reflog of %s line %d", name, i+1)
+		}
+		entries = append(entries, ent)
+	}
+	return entries, nil
+}
+
+// ParseReflogLine parse a line of the reflog file.
+//
+// line format: "<old> <new> <name> <<email>> <unix-time> <tz>\t<message>"
+func ParseReflogLine(line string) (*ReflogEntry, error) {
+	info, msg, _ := strings.Cut(line, "\t")
+	old, rest, ok1 := strings.Cut(info, " ")
+	newID, ident, ok2 := strings.Cut(rest, " ")
+	if !ok1 || !ok2 || !isHexHash(old) || !isHexHash(newID) {
+		return nil, errorx.Rawf("invalid reflog line %q", line)
+	}
+
+	ent := &ReflogEntry{Old: old, New: newID, Message: msg}
+	emailEnd := strings.LastIndexByte(ident, '>')
+	emailStart := strings.LastIndexByte(ident[:max(emailEnd, 0)], '<')
+	if emailStart < 0 || emailEnd < emailStart {
+		return nil, errorx.Rawf("invalid reflog ident %q", ident)
+	}
+
+	ent.Committer.Name = strings.TrimSpace(ident[:emailStart])
+	ent.Committer.Email = ident[emailStart+1 : emailEnd]
+
+	// time and timezone. eg: "1704265445 +0800"
+	fields := strings.Fields(ident[emailEnd+1:])
+	if len(fields) == 2 {
+		sec, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, errorx.Rawf("invalid reflog time %q", fields[0])
+		}
+
+		ent.Committer.When = time.Unix(sec, 0)
+		if tz, err := time.Parse("-0700", fields[1]); err == nil {
+			ent.Committer.When = ent.Committer.When.In(tz.Location())
+		}
+	}
+	return ent, nil
+}
+
+// read the loose ref file. the content is "<hash>" or "ref: <target>"
+func (rr *RefReader) readLoose(name string) (*RefInfo, error) {
+	path := rr.dirs.Path(name)
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		// eg: read "refs/heads/fea" but it is a dir of "refs/heads/fea/a"
+		if os.IsNotExist(err) || !fsutil.IsFile(path) {
+			return nil, errorx.Wrapf(ErrRefNotFound, "ref %q not found", name)
+		}
+		return nil, err
+	}
+
+	line := strings.TrimSpace(string(bs))
+	if target, ok := strings.CutPrefix(line, "ref:"); ok {
+		return &RefInfo{Name: name, Target: strings.TrimSpace(target)}, nil
+	}
+
+	if !isHexHash(line) {
+		return nil, errorx.Rawf("invalid content of ref %q", name)
+	}
+	return &RefInfo{Name: name, Hash: line}, nil
+}
+
+// load the packed-refs file in common dir. will reuse the parsed on the file not changed.
+func (rr *RefReader) loadPacked() (map[string]*RefInfo, error) {
+	path := filepath.Join(rr.dirs.CommonDir, "packed-refs")
+
+	var st fileStamp
+	if fi, err := os.Stat(path); err == nil {
+		st = fileStamp{mtime: fi.ModTime().UnixNano(), size: fi.Size()}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.packed != nil && rr.packedStamp == st {
+		return rr.packed, nil
+	}
+
+	packed := make(map[string]*RefInfo)
+	if st.size > 0 {
+		fh, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer fh.Close()
+
+		if packed, err = ParsePackedRefs(fh); err != nil {
+			return nil, err
+		}
+	}
+
+	rr.packed, rr.packedStamp = packed, st
+	return packed, nil
+}
+
+// ParsePackedRefs parse the packed-refs file contents. returns map: ref name => RefInfo
+//
+// Contents eg:
+//
+//	# pack-refs with: peeled fully-peeled sorted
+//	3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61 refs/heads/main
+//	de9b8fccc19994276650e10fffec20da287f543c refs/tags/v1.0.0
+//	^3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61
+func ParsePackedRefs(r io.Reader) (map[string]*RefInfo, error) {
+	refs := make(map[string]*RefInfo)
+	var last *RefInfo
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 4096), 1024*1024)
+	for num := 1; s.Scan(); num++ {
+		line := strings.TrimRight(s.Text(), "\r")
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		// peeled line of the previous tag
+		if line[0] == '^' {
+			if last == nil || !isHexHash(line[1:]) {
+				return nil, errorx.Rawf("packed-refs line %d: invalid peeled line %q", num, line)
+			}
+			last.Peeled = line[1:]
+			continue
+		}
+
+		hash, name, ok := strings.Cut(line, " ")
+		if !ok || !isHexHash(hash) || name == "" {
+			return nil, errorx.Rawf("packed-refs line %d: invalid ref line %q", num, line)
+		}
+
+		last = &RefInfo{Name: name, Hash: hash}
+		refs[name] = last
+	}
+	return refs, s.Err()
+}
+
+// check is a SHA-1 or SHA-256 hex object ID
+func isHexHash(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
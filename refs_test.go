@@ -0,0 +1,129 @@
+package gitw_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/gitw/gitwtest"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParsePackedRefs(t *testing.T) {
+	refs, err := gitw.ParsePackedRefs(strings.NewReader(`# pack-refs with: peeled fully-peeled sorted
+3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61 refs/heads/main
+de9b8fccc19994276650e10fffec20da287f543c refs/tags/v1.0.0
+^3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61
+`))
+	assert.NoErr(t, err)
+	assert.Len(t, refs, 2)
+	assert.Eq(t, "3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61", refs["refs/heads/main"].Hash)
+	assert.Eq(t, "", refs["refs/heads/main"].Peeled)
+	assert.Eq(t, "3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61", refs["refs/tags/v1.0.0"].Peeled)
+	assert.Eq(t, "v1.0.0", refs["refs/tags/v1.0.0"].ShortName())
+
+	_, err = gitw.ParsePackedRefs(strings.NewReader("^3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61\n"))
+	assert.Err(t, err)
+}
+
+func TestParseReflogLine(t *testing.T) {
+	ent, err := gitw.ParseReflogLine("0000000000000000000000000000000000000000 3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61 inhere <in.798@qq.com> 1704265445 +0800\tcommit (initial): first commit")
+	assert.NoErr(t, err)
+	assert.Eq(t, "3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61", ent.New)
+	assert.Eq(t, "inhere", ent.Committer.Name)
+	assert.Eq(t, "in.798@qq.com", ent.Committer.Email)
+	assert.Eq(t, "2024-01-03T15:04:05+08:00", ent.Committer.When.Format("2006-01-02T15:04:05-07:00"))
+	assert.Eq(t, "commit (initial): first commit", ent.Message)
+
+	_, err = gitw.ParseReflogLine("invalid line")
+	assert.Err(t, err)
+}
+
+func TestRefReader(t *testing.T) {
+	r := newTempRepo(t)
+	assert.NoErr(t, r.Cmd("tag", "v1.0.0", "HEAD~1").Run())
+	assert.NoErr(t, r.Cmd("tag", "-a", "-m", "release v1.1.0", "v1.1.0").Run())
+	assert.NoErr(t, r.Cmd("branch", "fea/a").Run())
+	assert.NoErr(t, r.Cmd("pack-refs", "--all").Run())
+	// loose ref after packed
+	assert.NoErr(t, r.Cmd("tag", "v1.2.0").Run())
+	assert.NoErr(t, r.Cmd("update-ref", "refs/remotes/origin/main", "HEAD").Run())
+	assert.NoErr(t, r.Cmd("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/main").Run())
+
+	rr, err := r.RefReader()
+	assert.NoErr(t, err)
+	head := r.LastCommitID()
+
+	br, err := rr.HeadBranch()
+	assert.NoErr(t, err)
+	assert.Eq(t, "main", br)
+
+	for _, name := range []string{"HEAD", "main", "refs/heads/main", "fea/a", "origin", "origin/main", "v1.2.0"} {
+		hash, err := rr.Resolve(name)
+		assert.NoErr(t, err, name)
+		assert.Eq(t, head, hash, name)
+	}
+
+	_, err = rr.Resolve("not-exists")
+	assert.True(t, errors.Is(err, gitw.ErrRefNotFound))
+	assert.False(t, rr.Exists("refs/heads/fea"))
+
+	// packed and peeled tag
+	ref, err := rr.Ref("refs/tags/v1.1.0")
+	assert.NoErr(t, err)
+	assert.NotEq(t, head, ref.Hash)
+	assert.Eq(t, head, ref.Peeled)
+
+	refs, err := rr.Refs(gitw.RefsTagsPrefix)
+	assert.NoErr(t, err)
+	assert.Len(t, refs, 3)
+	assert.Eq(t, "refs/tags/v1.0.0", refs[0].Name)
+
+	ref, err = rr.Ref("refs/remotes/origin/HEAD")
+	assert.NoErr(t, err)
+	assert.True(t, ref.IsSymbolic())
+	assert.Eq(t, "refs/remotes/origin/main", ref.Target)
+
+	logs, err := rr.Reflog("HEAD")
+	assert.NoErr(t, err)
+	assert.Len(t, logs, 2)
+	assert.Eq(t, head, logs[0].New)
+	assert.Eq(t, logs[1].New, logs[0].Old)
+	assert.StrContains(t, logs[1].Message, "first commit")
+
+	// linked worktree has own HEAD
+	wtPath := filepath.Join(r.Dir(), "..", "refs-wt")
+	assert.NoErr(t, r.AddWorktree(wtPath, "", &gitw.WorktreeAddOpts{Branch: "fea/wt"}))
+	wr := gitw.NewRepo(wtPath).WithConfigFn(func(cfg *gitw.RepoConfig) {
+		cfg.NativeRefs = true
+	})
+	assert.Eq(t, "fea/wt", wr.CurBranchName())
+	assert.True(t, wr.HasLocalBranch("fea/a"))
+	assert.Eq(t, []string{"v1.0.0", "v1.1.0", "v1.2.0"}, wr.Tags())
+}
+
+func TestRepo_NativeRefs(t *testing.T) {
+	r := newTempRepo(t)
+	head := r.LastCommitID()
+
+	fr := gitwtest.NewFakeRunner()
+	nr := gitw.NewRepo(r.Dir()).WithRunner(fr).WithConfigFn(func(cfg *gitw.RepoConfig) {
+		cfg.NativeRefs = true
+	})
+
+	assert.Eq(t, "main", nr.CurBranchName())
+	assert.Eq(t, head, nr.LastCommitID())
+	assert.True(t, nr.HasLocalBranch("main"))
+	assert.False(t, nr.HasLocalBranch("dev"))
+	assert.Empty(t, nr.Tags())
+	assert.Empty(t, fr.Calls())
+
+	// invalid packed-refs, fallback to git
+	assert.NoErr(t, os.WriteFile(filepath.Join(r.Dir(), ".git", "packed-refs"), []byte("invalid line\n"), 0644))
+	fr.On("branch -v --all", "* main 7e9a1b2 feat: first commit\n  dev  7e9a1b2 feat: first commit\n")
+	assert.True(t, nr.HasLocalBranch("dev"))
+	assert.Eq(t, []string{"branch -v --all"}, fr.Calls())
+}
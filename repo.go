@@ -1,7 +1,12 @@
 package gitw
 
 import (
+	"context"
+	"errors"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gookit/gitw/brinfo"
 	"github.com/gookit/goutil/arrutil"
@@ -10,58 +15,92 @@ import (
 	"github.com/gookit/goutil/maputil"
 	"github.com/gookit/goutil/strutil"
 	"github.com/gookit/goutil/sysutil/cmdr"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
-	cacheRemoteNames   = "rmtNames"
 	cacheRemoteInfos   = "rmtInfos"
 	cacheLastCommitID  = "lastCID"
 	cacheCurrentBranch = "curBranch"
 	cacheMaxTagVersion = "maxVersion"
 	cacheUpstreamPath  = "upstreamTo"
+	cacheStatusInfo    = "status"
+	cacheBranchInfos   = "branches"
+	cacheBranchTracks  = "brTracks"
+	cacheDefaultBranch = "defBranch"
+	// sub key by remote name. eg: rmtDefBranch:origin
+	cacheRemoteDefBranch = "rmtDefBranch"
 )
 
 // RepoConfig struct
 type RepoConfig struct {
-	// DefaultBranch name, default is DefaultBranchName
+	// DefaultBranch name, default is empty for auto-detect. see Repo.DefaultBranch()
 	DefaultBranch string
 	// DefaultRemote name, default is DefaultRemoteName
 	DefaultRemote string
+	// AutoRefresh check the .git state changes(HEAD, index, refs, config) on access the cached data,
+	// and drop the stale cache automatically. Useful for long-running processes.
+	//
+	// NOTE: only check the mtime of the refs dirs and one level sub dirs(eg: refs/remotes/origin),
+	// the changes of deeper refs(eg: refs/heads/fea/a/b) may not be detected, call Invalidate() for them.
+	AutoRefresh bool
+	// NativeRefs read the HEAD, refs and packed-refs in-process for some methods,
+	// without spawn git process. will fallback to run git on read failed. see RefReader
+	NativeRefs bool
+	// NativeConfig parse the git config files in-process for load the remote infos,
+	// without run "git remote -v". will fallback to run git on load failed. see Repo.LoadConfig
+	NativeConfig bool
 }
 
 func newDefaultCfg() *RepoConfig {
 	return &RepoConfig{
-		DefaultBranch: DefaultBranchName,
 		DefaultRemote: DefaultRemoteName,
 	}
 }
 
 // Repo struct
+//
+// The Repo is safe for concurrent use, but the settings(eg: WithConfig, WithRunner)
+// should be done before share it.
 type Repo struct {
 	gw *GitWrap
 	// the repo dir
 	dir string
-	// save last error
-	err error
 	// config
 	cfg *RepoConfig
+	// the cache and state, is shared with the Repo copies by WithContext, WithTimeout
+	*repoState
+}
 
-	// status info
-	statusInfo *StatusInfo
-
-	// branch infos for the repo
-	branchInfos *BranchInfos
+// the shared state of the Repo
+type repoState struct {
+	// save last error
+	err error
+	// lock for the cache and err
+	mu sync.RWMutex
+	// loading the cache sections, call once on concurrent.
+	sf singleflight.Group
+	// generation of the cache, increase on Invalidate()
+	gen uint64
+	// state stamps of the .git files, for AutoRefresh
+	stamps map[string]fileStamp
+	// resolved git dirs of the repo
+	gitDirs *GitDirs
+	// native ref reader, for NativeRefs
+	refReader *RefReader
+	// cache some information of the repo. eg: status info, branch infos, remote infos
+	cache maputil.Data
+}
 
-	// remoteNames
-	remoteNames []string
-	// remoteInfosMp
+// repoRemotes the remote names and infos of the repo
+type repoRemotes struct {
+	// names in git output order
+	names []string
+	// infos map.
 	//
 	// Example:
 	// 	{origin: {fetch: remote info, push: remote info}}
-	remoteInfosMp map[string]RemoteInfos
-
-	// cache some information of the repo
-	cache maputil.Data
+	infos map[string]RemoteInfos
 }
 
 // NewRepo create Repo object
@@ -69,10 +108,10 @@ func NewRepo(dir string) *Repo {
 	return &Repo{
 		dir: dir,
 		cfg: newDefaultCfg(),
-		// init gw
-		gw: NewWithWorkdir(dir),
+		// init gw. pin the locale for parse git output
+		gw: NewWithWorkdir(dir).WithLocale("C"),
 		// cache some information
-		cache: make(maputil.Data, 8),
+		repoState: &repoState{cache: make(maputil.Data, 8)},
 	}
 }
 
@@ -100,12 +139,69 @@ func (r *Repo) PrintCmdOnExec() *Repo {
 	return r
 }
 
-// SetDryRun settings.
+// Use add exec hooks for run git commands of the repo. see GitWrap.Use()
+func (r *Repo) Use(hooks ...ExecHook) *Repo {
+	r.gw.Use(hooks...)
+	return r
+}
+
+// OnBeforeExec add before exec hook for run git commands of the repo.
+func (r *Repo) OnBeforeExec(fn func(gw *GitWrap)) *Repo {
+	r.gw.OnBeforeExec(fn)
+	return r
+}
+
+// OnAfterExec add after exec hook for run git commands of the repo.
+func (r *Repo) OnAfterExec(fn func(gw *GitWrap, res *ExecResult)) *Repo {
+	r.gw.OnAfterExec(fn)
+	return r
+}
+
+// WithContext get a copy of the repo, which run git commands with the context.
+// the copy shares the config, cache, last error and dry run plan with current repo.
+//
+// Usage:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	err := repo.WithContext(ctx).FetchAll()
+func (r *Repo) WithContext(ctx context.Context) *Repo {
+	nr := r.copy()
+	nr.gw.WithContext(ctx)
+	return nr
+}
+
+// WithTimeout get a copy of the repo, which run each git command with the timeout. see WithContext()
+func (r *Repo) WithTimeout(timeout time.Duration) *Repo {
+	nr := r.copy()
+	nr.gw.WithTimeout(timeout)
+	return nr
+}
+
+// shallow copy the repo, with a copied GitWrap.
+func (r *Repo) copy() *Repo {
+	nr := *r
+	nr.gw = r.gw.New(r.gw.Args...)
+	return &nr
+}
+
+// WithRunner set custom runner for run git commands of the repo. see Runner
+func (r *Repo) WithRunner(runner Runner) *Repo {
+	r.gw.WithRunner(runner)
+	return r
+}
+
+// SetDryRun settings. on dry run, the mutating commands will be recorded to the DryRunPlan.
 func (r *Repo) SetDryRun(dr bool) *Repo {
-	r.gw.DryRun = dr
+	r.gw.WithDryRun(dr)
 	return r
 }
 
+// DryRunPlan get the recorded mutating commands on dry run.
+func (r *Repo) DryRunPlan() *DryRunPlan {
+	return r.gw.DryRunPlan()
+}
+
 // Init run git init for the repo dir.
 func (r *Repo) Init() error {
 	return r.gw.Init().Run()
@@ -128,7 +224,7 @@ func (r *Repo) Info() *RepoInfo {
 		Upstream: r.UpstreamPath(),
 	}
 
-	rt := r.loadRemoteInfos().FirstRemoteInfo()
+	rt := r.FirstRemoteInfo()
 	if rt == nil {
 		return ri
 	}
@@ -136,13 +232,7 @@ func (r *Repo) Info() *RepoInfo {
 	ri.Name = rt.Repo
 	ri.Path = rt.Path()
 	ri.URL = rt.URLOrBuild()
-
-	remotes := make(map[string]string)
-	for name, infos := range r.remoteInfosMp {
-		remotes[name] = infos.FetchInfo().URL
-	}
-
-	ri.Remotes = remotes
+	ri.Remotes = r.RemoteLines()
 	return ri
 }
 
@@ -198,42 +288,27 @@ func (r *Repo) MaxTag() string {
 
 // LargestTag get max tag version of the repo
 func (r *Repo) LargestTag() string {
-	tagVer := r.cache.Str(cacheMaxTagVersion)
-	if len(tagVer) > 0 {
-		return tagVer
-	}
-
-	tags := r.TagsSortedByRefName()
-
-	if len(tags) > 0 {
-		r.cache.Set(cacheMaxTagVersion, tags[0])
-		return tags[0]
-	}
-	return ""
+	return r.LargestTagByTagType(RefNameTagType)
 }
 
 // LargestTagByTagType get max tag version of the repo by tag_type
 func (r *Repo) LargestTagByTagType(tagType int) string {
-	tagVer := r.cache.Str(cacheMaxTagVersion)
-	if len(tagVer) > 0 {
-		return tagVer
-	}
-
-	tags := make([]string, 0, 2)
-	switch tagType {
-	case CreatorDateTagType:
-		tags = append(tags, r.TagsSortedByCreatorDate()...)
-	case DescribeTagType:
-		tags = append(tags, r.TagByDescribe(""))
-	default:
-		tags = append(tags, r.TagsSortedByRefName()...)
-	}
+	return r.loadCacheStr(cacheMaxTagVersion, func() (string, bool) {
+		tags := make([]string, 0, 2)
+		switch tagType {
+		case CreatorDateTagType:
+			tags = append(tags, r.TagsSortedByCreatorDate()...)
+		case DescribeTagType:
+			tags = append(tags, r.TagByDescribe(""))
+		default:
+			tags = append(tags, r.TagsSortedByRefName()...)
+		}
 
-	if len(tags) > 0 {
-		r.cache.Set(cacheMaxTagVersion, tags[0])
-		return tags[0]
-	}
-	return ""
+		if len(tags) > 0 && tags[0] != "" {
+			return tags[0], true
+		}
+		return "", false
+	})
 }
 
 // PrevMaxTag get second-largest tag of the repo
@@ -319,6 +394,16 @@ func (r *Repo) TagByDescribe(current string) (ver string) {
 
 // Tags get repo tags list
 func (r *Repo) Tags() []string {
+	if rr := r.nativeRefs(); rr != nil {
+		if refs, err := rr.Refs(RefsTagsPrefix); err == nil {
+			ss := make([]string, 0, len(refs))
+			for _, ref := range refs {
+				ss = append(ss, ref.ShortName())
+			}
+			return ss
+		}
+	}
+
 	ss, err := r.gw.Tag("-l").OutputLines()
 	if err != nil {
 		r.setErr(err)
@@ -344,20 +429,23 @@ func (r *Repo) LastAbbrevID() string {
 
 // LastCommitID value
 func (r *Repo) LastCommitID() string {
-	lastCID := r.cache.Str(cacheLastCommitID)
-	if len(lastCID) > 0 {
-		return lastCID
-	}
+	return r.loadCacheStr(cacheLastCommitID, func() (string, bool) {
+		if rr := r.nativeRefs(); rr != nil {
+			if hash, err := rr.Resolve(HeadFile); err == nil {
+				return hash, true
+			}
+		}
 
-	// by: git log -1 --format='%H'
-	lastCID, err := r.gw.Log("-1", "--format=%H").Output()
-	if err != nil {
-		r.setErr(err)
-		return ""
-	}
+		// by: git log -1 --format='%H'
+		str, err := r.gw.Log("-1", "--format=%H").Output()
+		if err != nil {
+			r.setErr(err)
+			return "", false
+		}
 
-	r.cache.Set(cacheLastCommitID, lastCID)
-	return lastCID
+		lastCID := cmdr.FirstLine(str)
+		return lastCID, lastCID != ""
+	})
 }
 
 // -------------------------------------------------
@@ -366,17 +454,21 @@ func (r *Repo) LastCommitID() string {
 
 // StatusInfo get status info of the repo
 func (r *Repo) StatusInfo() *StatusInfo {
-	if r.statusInfo == nil {
-		r.statusInfo = &StatusInfo{}
-		lines, err := r.gw.Status("-bs", "-u").OutputLines()
+	val := r.loadCache(cacheStatusInfo, func() (any, bool) {
+		out, err := r.gw.Status("--porcelain=v2", "--branch", "-z", "-u").Output()
 		if err != nil {
 			r.setErr(err)
-			return nil
+			return (*StatusInfo)(nil), false
 		}
 
-		r.statusInfo.FromLines(lines)
-	}
-	return r.statusInfo
+		si, err := ParseStatusV2(out)
+		if err != nil {
+			r.setErr(err)
+			return (*StatusInfo)(nil), false
+		}
+		return si, true
+	})
+	return val.(*StatusInfo)
 }
 
 // -------------------------------------------------
@@ -384,64 +476,66 @@ func (r *Repo) StatusInfo() *StatusInfo {
 // -------------------------------------------------
 
 func (r *Repo) HasBranch(branch string, remote ...string) bool {
-	return r.loadBranchInfos().branchInfos.IsExists(branch, remote...)
+	return r.loadBranchInfos().IsExists(branch, remote...)
 }
 
 func (r *Repo) HasRemoteBranch(branch, remote string) bool {
-	return r.loadBranchInfos().branchInfos.HasRemote(branch, remote)
+	return r.loadBranchInfos().HasRemote(branch, remote)
 }
 
 func (r *Repo) HasLocalBranch(branch string) bool {
-	return r.loadBranchInfos().branchInfos.HasLocal(branch)
+	if rr := r.nativeRefs(); rr != nil {
+		// fallback to git on read refs failed. eg: invalid packed-refs
+		_, err := rr.Ref(RefsHeadsPrefix + branch)
+		if err == nil || errors.Is(err, ErrRefNotFound) {
+			return err == nil
+		}
+	}
+	return r.loadBranchInfos().HasLocal(branch)
 }
 
 // BranchInfos get branch infos of the repo
 func (r *Repo) BranchInfos() *BranchInfos {
-	return r.loadBranchInfos().branchInfos
+	return r.loadBranchInfos()
 }
 
 // ReloadBranches reload branch infos of the repo
 func (r *Repo) ReloadBranches() *BranchInfos {
-	r.branchInfos = nil
-	return r.loadBranchInfos().branchInfos
+	r.Invalidate(SectionBranches)
+	return r.loadBranchInfos()
 }
 
 // CurBranchInfo get current branch info of the repo
 func (r *Repo) CurBranchInfo() *BranchInfo {
-	return r.loadBranchInfos().branchInfos.Current()
+	return r.loadBranchInfos().Current()
 }
 
 // BranchInfo find branch info by name, if remote is empty, find local branch
 func (r *Repo) BranchInfo(branch string, remote ...string) *BranchInfo {
-	return r.loadBranchInfos().branchInfos.GetByName(branch, remote...)
+	return r.loadBranchInfos().GetByName(branch, remote...)
 }
 
 // SearchBranchV2 search branch infos by keywords
 func (r *Repo) SearchBranchV2(m brinfo.BranchMatcher, opt *SearchOpt) []*BranchInfo {
-	return r.loadBranchInfos().branchInfos.SearchV2(m, opt)
+	return r.loadBranchInfos().SearchV2(m, opt)
 }
 
 // SearchBranches search branch infos by name
 func (r *Repo) SearchBranches(name string, flag uint8) []*BranchInfo {
-	return r.loadBranchInfos().branchInfos.Search(name, flag)
+	return r.loadBranchInfos().Search(name, flag)
 }
 
 // load branch infos
-func (r *Repo) loadBranchInfos() *Repo {
-	// has loaded
-	if r.branchInfos != nil {
-		return r
-	}
-
-	str, err := r.gw.Branch("-v", "--all").Output()
-	if err != nil {
-		r.setErr(err)
-		r.branchInfos = EmptyBranchInfos()
-		return r
-	}
-
-	r.branchInfos = NewBranchInfos(str).Parse()
-	return r
+func (r *Repo) loadBranchInfos() *BranchInfos {
+	val := r.loadCache(cacheBranchInfos, func() (any, bool) {
+		str, err := r.gw.Branch("-v", "--all").Output()
+		if err != nil {
+			r.setErr(err)
+			return EmptyBranchInfos(), true
+		}
+		return NewBranchInfos(str).Parse(), true
+	})
+	return val.(*BranchInfos)
 }
 
 // HeadBranchName return current branch name
@@ -449,11 +543,10 @@ func (r *Repo) HeadBranchName() string { return r.CurBranchName() }
 
 // CurBranchName return current branch name
 func (r *Repo) CurBranchName() string {
-	brName := r.cache.Str(cacheCurrentBranch)
-	if len(brName) > 0 {
-		return brName
-	}
+	return r.loadCacheStr(cacheCurrentBranch, r.fetchCurBranchName)
+}
 
+func (r *Repo) fetchCurBranchName() (string, bool) {
 	// 	cat .git/HEAD
 	// OR
 	// 	git branch --show-current // on high version git
@@ -463,18 +556,25 @@ func (r *Repo) CurBranchName() string {
 	// Or
 	// 	git rev-parse --abbrev-ref -q HEAD // on init project, will error
 
+	if rr := r.nativeRefs(); rr != nil {
+		if brName, err := rr.HeadBranch(); err == nil {
+			return brName, true
+		}
+	}
+
 	str := r.gw.Branch("--show-current").SafeOutput()
 	if len(str) == 0 {
-		str, r.err = r.gw.RevParse("--abbrev-ref", "-q", "HEAD").Output()
-		if r.err != nil {
-			return ""
+		var err error
+		str, err = r.gw.RevParse("--abbrev-ref", "-q", "HEAD").Output()
+		if err != nil {
+			r.setErr(err)
+			return "", false
 		}
 	}
 
 	// eg: fea_pref
-	brName = cmdr.FirstLine(str)
-	r.cache.Set(cacheCurrentBranch, brName)
-	return brName
+	brName := cmdr.FirstLine(str)
+	return brName, brName != ""
 }
 
 // SetUpstreamTo set the branch upstream remote branch.
@@ -514,13 +614,13 @@ func (r *Repo) HasRemote(name string) bool {
 
 // RemoteNames get
 func (r *Repo) RemoteNames() []string {
-	return r.loadRemoteInfos().remoteNames
+	return r.loadRemoteInfos().names
 }
 
 // RemoteLines get like: {origin: url, other: url}
 func (r *Repo) RemoteLines() map[string]string {
 	remotes := make(map[string]string)
-	for name, infos := range r.loadRemoteInfos().remoteInfosMp {
+	for name, infos := range r.loadRemoteInfos().infos {
 		remotes[name] = infos.FetchInfo().URL
 	}
 
@@ -534,15 +634,12 @@ func (r *Repo) RemoteLines() map[string]string {
 //
 //	git rev-parse --abbrev-ref @{u}
 func (r *Repo) UpstreamPath() string {
-	path := r.cache.Str(cacheUpstreamPath)
-
-	// RUN: git rev-parse --abbrev-ref @{u}
-	if path == "" {
-		path = r.Git().RevParse("--abbrev-ref", "@{u}").SafeOutput()
-		r.cache.Set(cacheUpstreamPath, strings.TrimSpace(path))
-	}
-
-	return path
+	return r.loadCacheStr(cacheUpstreamPath, func() (string, bool) {
+		// RUN: git rev-parse --abbrev-ref @{u}
+		path := strings.TrimSpace(r.Git().RevParse("--abbrev-ref", "@{u}").SafeOutput())
+		// not cache on no upstream, it maybe set later by: git branch -u
+		return path, path != ""
+	})
 }
 
 // UpstreamRemote get current upstream remote name.
@@ -563,12 +660,11 @@ func (r *Repo) UpstreamBranch() string {
 
 // RemoteInfos get by remote name
 func (r *Repo) RemoteInfos(remote string) RemoteInfos {
-	r.loadRemoteInfos()
-
-	if len(r.remoteInfosMp) == 0 {
+	rs := r.loadRemoteInfos()
+	if len(rs.infos) == 0 {
 		return nil
 	}
-	return r.remoteInfosMp[remote]
+	return rs.infos[remote]
 }
 
 // DefaultRemoteInfo get
@@ -583,12 +679,11 @@ func (r *Repo) FirstRemoteInfo(typ ...string) *RemoteInfo {
 
 // RandomRemoteInfo get
 func (r *Repo) RandomRemoteInfo(typ ...string) *RemoteInfo {
-	r.loadRemoteInfos()
-
-	if len(r.remoteNames) == 0 {
+	names := r.RemoteNames()
+	if len(names) == 0 {
 		return nil
 	}
-	return r.RemoteInfo(r.remoteNames[0], typ...)
+	return r.RemoteInfo(names[0], typ...)
 }
 
 // RemoteInfo get by remote name and type.
@@ -619,20 +714,26 @@ func (r *Repo) RemoteInfo(remote string, typ ...string) *RemoteInfo {
 
 // AllRemoteInfos get
 func (r *Repo) AllRemoteInfos() map[string]RemoteInfos {
-	return r.loadRemoteInfos().remoteInfosMp
+	return r.loadRemoteInfos().infos
 }
 
-// AllRemoteInfos get
-func (r *Repo) loadRemoteInfos() *Repo {
-	// has loaded
-	if len(r.remoteNames) > 0 {
-		return r
+// load remote names and infos
+func (r *Repo) loadRemoteInfos() *repoRemotes {
+	val := r.loadCache(cacheRemoteInfos, r.fetchRemoteInfos)
+	return val.(*repoRemotes)
+}
+
+func (r *Repo) fetchRemoteInfos() (any, bool) {
+	if r.cfg.NativeConfig {
+		if cfg, err := r.LoadConfig(); err == nil {
+			return r.remotesFromConfig(cfg), true
+		}
 	}
 
 	str, err := r.gw.Remote("-v").Output()
 	if err != nil {
 		r.setErr(err)
-		return r
+		return &repoRemotes{}, false
 	}
 
 	// origin  https://github.com/gookit/gitw.git (fetch)
@@ -674,11 +775,7 @@ func (r *Repo) loadRemoteInfos() *Repo {
 		}
 	}
 
-	if len(names) > 0 {
-		r.remoteNames = names
-		r.remoteInfosMp = rmp
-	}
-	return r
+	return &repoRemotes{names: names, infos: rmp}, true
 }
 
 // reset last error
@@ -686,14 +783,87 @@ func (r *Repo) loadRemoteInfos() *Repo {
 // 	r.err = nil
 // }
 
-// ReadConfig contents from REPO/.git/config
+// GitDirs resolve the git dirs of the repo. see ResolveGitDir()
+//
+// The resolved result will be cached, and shared by the Repo copies.
+func (r *Repo) GitDirs() (*GitDirs, error) {
+	r.mu.RLock()
+	dirs := r.gitDirs
+	r.mu.RUnlock()
+	if dirs != nil {
+		return dirs, nil
+	}
+
+	dirs, err := r.gw.ResolveGitDir()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.gitDirs = dirs
+	r.mu.Unlock()
+	return dirs, nil
+}
+
+// RefReader get the native ref reader of the repo. see NewRefReader()
+func (r *Repo) RefReader() (*RefReader, error) {
+	r.mu.RLock()
+	rr := r.refReader
+	r.mu.RUnlock()
+	if rr != nil {
+		return rr, nil
+	}
+
+	dirs, err := r.GitDirs()
+	if err != nil {
+		return nil, err
+	}
+	if rr, err = NewRefReader(dirs); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.refReader = rr
+	r.mu.Unlock()
+	return rr, nil
+}
+
+// get the native ref reader on enable NativeRefs, returns nil on disabled or failed.
+func (r *Repo) nativeRefs() *RefReader {
+	if !r.cfg.NativeRefs {
+		return nil
+	}
+
+	rr, err := r.RefReader()
+	if err != nil {
+		return nil
+	}
+	return rr
+}
+
+// ReadConfig contents from REPO/.git/config. in linked worktree, it is in the common dir.
 func (r *Repo) ReadConfig() []byte {
-	return fsutil.GetContents(fsutil.JoinPaths(r.dir, GitDir, ConfFile))
+	return r.readGitFile(ConfFile)
 }
 
 // ReadHEAD contents from REPO/.git/HEAD
 func (r *Repo) ReadHEAD() []byte {
-	return fsutil.GetContents(fsutil.JoinPaths(r.dir, GitDir, HeadFile))
+	return r.readGitFile(HeadFile)
+}
+
+func (r *Repo) readGitFile(name string) []byte {
+	dirs, err := r.GitDirs()
+	if err != nil {
+		r.setErr(err)
+		return nil
+	}
+
+	bs, err := os.ReadFile(dirs.Path(name))
+	if err != nil {
+		r.setErr(err)
+		return nil
+	}
+	return bs
 }
 
 // -------------------------------------------------
@@ -706,15 +876,25 @@ func (r *Repo) IsValid() bool { return r.IsGitRepo() }
 // IsGitRepo check the dir is git repo
 func (r *Repo) IsGitRepo() bool { return r.gw.IsGitRepo() }
 
-// reset last error
+// save last error. the error from run git is *GitError
 func (r *Repo) setErr(err error) {
 	if err != nil {
+		r.mu.Lock()
 		r.err = err
+		r.mu.Unlock()
 	}
 }
 
-// Err get last error
+// Err get last error. can use errors.As() to get *GitError for more details.
+//
+// Usage:
+//
+//	if errors.Is(repo.Err(), gitw.ErrNotRepo) {
+//		// ...
+//	}
 func (r *Repo) Err() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.err
 }
 
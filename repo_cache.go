@@ -0,0 +1,210 @@
+package gitw
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// cache sections of the Repo, for Invalidate() the cached data.
+const (
+	// SectionHead current branch name, last commit ID and upstream path.
+	SectionHead = "head"
+	// SectionTags the max tag version.
+	SectionTags = "tags"
+	// SectionStatus the status info.
+	SectionStatus = "status"
+	// SectionBranches the branch infos and tracking infos.
+	SectionBranches = "branches"
+	// SectionRemotes the remote names, infos and the default branch.
+	SectionRemotes = "remotes"
+)
+
+// AllSections of the Repo cache
+var AllSections = []string{SectionHead, SectionTags, SectionStatus, SectionBranches, SectionRemotes}
+
+// cache keys of each section
+var sectionKeys = map[string][]string{
+	SectionHead:     {cacheCurrentBranch, cacheLastCommitID, cacheUpstreamPath},
+	SectionTags:     {cacheMaxTagVersion},
+	SectionStatus:   {cacheStatusInfo},
+	SectionBranches: {cacheBranchInfos, cacheBranchTracks},
+	SectionRemotes:  {cacheRemoteInfos, cacheDefaultBranch, cacheRemoteDefBranch},
+}
+
+// Invalidate drop the cached data of the sections. eg: SectionHead, SectionBranches
+//
+// Usage:
+//
+//	repo.Invalidate(gitw.SectionHead, gitw.SectionStatus)
+func (r *Repo) Invalidate(sections ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.gen++
+	for _, sec := range sections {
+		for _, key := range sectionKeys[sec] {
+			delete(r.cache, key)
+			// the sub keys of the key. eg: "rmtDefBranch:origin"
+			for k := range r.cache {
+				if strings.HasPrefix(k, key+":") {
+					delete(r.cache, k)
+				}
+			}
+		}
+	}
+}
+
+// Refresh drop all cached data of the repo, will reload on next access.
+func (r *Repo) Refresh() {
+	r.Invalidate(AllSections...)
+}
+
+// getCache value by key
+func (r *Repo) getCache(key string) (any, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	val, ok := r.cache[key]
+	return val, ok
+}
+
+// loadCache get cached value by key, or load it by fn.
+//
+// The concurrent loads for same key will only call fn once.
+// fn return ok=false will not cache the value. eg: on run git failed.
+func (r *Repo) loadCache(key string, fn func() (val any, ok bool)) any {
+	if r.cfg.AutoRefresh {
+		r.checkChanges()
+	}
+
+	if val, ok := r.getCache(key); ok {
+		return val
+	}
+
+	r.mu.RLock()
+	gen := r.gen
+	r.mu.RUnlock()
+
+	// with generation: not share the loading result started before Invalidate()
+	val, _, _ := r.sf.Do(key+"@"+strconv.FormatUint(gen, 10), func() (any, error) {
+		if val, ok := r.getCache(key); ok {
+			return val, nil
+		}
+
+		val, ok := fn()
+		if ok {
+			r.mu.Lock()
+			// not store on the cache has been invalidated while loading.
+			if r.gen == gen {
+				r.cache[key] = val
+			}
+			r.mu.Unlock()
+		}
+		return val, nil
+	})
+	return val
+}
+
+// loadCacheStr get cached string value by key, or load it by fn. see loadCache()
+func (r *Repo) loadCacheStr(key string, fn func() (string, bool)) string {
+	val := r.loadCache(key, func() (any, bool) {
+		return fn()
+	})
+
+	str, _ := val.(string)
+	return str
+}
+
+// the watched state files in .git dir, and the sections will be invalidated on it changed.
+var stateFiles = []struct {
+	path     string
+	isDir    bool
+	sections []string
+}{
+	// checkout, commit, reset
+	{"HEAD", false, []string{SectionHead, SectionStatus, SectionBranches}},
+	{"logs/HEAD", false, []string{SectionHead, SectionStatus, SectionBranches}},
+	// add, rm, checkout
+	{"index", false, []string{SectionStatus}},
+	{"packed-refs", false, []string{SectionHead, SectionTags, SectionStatus, SectionBranches}},
+	{"refs/heads", true, []string{SectionHead, SectionStatus, SectionBranches}},
+	{"refs/tags", true, []string{SectionTags}},
+	{"refs/remotes", true, []string{SectionStatus, SectionBranches, SectionRemotes}},
+	// remote add, set upstream
+	{"config", false, []string{SectionHead, SectionBranches, SectionRemotes}},
+}
+
+// fileStamp the state of a file, for check it is changed.
+type fileStamp struct {
+	mtime int64 // unix nano
+	size  int64
+}
+
+// checkChanges check the .git state files, drop the stale cache on it changed.
+func (r *Repo) checkChanges() {
+	dirs, err := r.GitDirs()
+	if err != nil {
+		return
+	}
+	stamps := readStateStamps(dirs)
+
+	r.mu.Lock()
+	old := r.stamps
+	r.stamps = stamps
+	r.mu.Unlock()
+
+	// first check: can't know the cached data is fresh.
+	if old == nil {
+		r.Refresh()
+		return
+	}
+
+	var sections []string
+	for _, sf := range stateFiles {
+		if stamps[sf.path] != old[sf.path] {
+			sections = append(sections, sf.sections...)
+		}
+	}
+
+	if len(sections) > 0 {
+		r.Invalidate(sections...)
+	}
+}
+
+// read state stamps of the .git files. for dir will use the latest mtime of it and the direct sub dirs.
+//
+// NOTE: not walk all ref files, it costs more than run git on the repo has many refs.
+func readStateStamps(dirs *GitDirs) map[string]fileStamp {
+	stamps := make(map[string]fileStamp, len(stateFiles))
+	for _, sf := range stateFiles {
+		path := dirs.Path(sf.path)
+		if !sf.isDir {
+			if fi, err := os.Stat(path); err == nil {
+				stamps[sf.path] = fileStamp{mtime: fi.ModTime().UnixNano(), size: fi.Size()}
+			}
+			continue
+		}
+
+		// loose refs: the dir mtime is changed on add, update(rename lock file) and delete ref.
+		fi, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		st := fileStamp{mtime: fi.ModTime().UnixNano()}
+		// the sub dirs. eg: refs/remotes/origin, refs/heads/fea
+		ents, _ := os.ReadDir(path)
+		for _, ent := range ents {
+			if !ent.IsDir() {
+				continue
+			}
+			if fi, err = ent.Info(); err == nil {
+				st.mtime = max(st.mtime, fi.ModTime().UnixNano())
+				st.size++ // dir count
+			}
+		}
+		stamps[sf.path] = st
+	}
+	return stamps
+}
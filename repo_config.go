@@ -0,0 +1,204 @@
+package gitw
+
+import (
+	"strings"
+
+	"github.com/gookit/gitw/gitconfig"
+	"github.com/gookit/goutil/strutil"
+	"github.com/gookit/goutil/sysutil/cmdr"
+)
+
+// LoadConfig load the merged git config of the repo by native parser, contains the system,
+// global, local, worktree scopes and the "-c" overrides of the GitWrap. see gitconfig.Load()
+//
+// Usage:
+//
+//	cfg, err := repo.LoadConfig()
+//	name := cfg.Get("user.name")
+//	e := cfg.Entry("user.email") // e.Scope, e.Origin
+func (r *Repo) LoadConfig() (*gitconfig.Config, error) {
+	opts := &gitconfig.Options{
+		Overrides: r.gw.Configs,
+		Getenv:    r.gw.getenv,
+	}
+
+	dirs, err := r.GitDirs()
+	if err == nil {
+		opts.GitDir, opts.CommonDir = dirs.GitDir, dirs.CommonDir
+		if rr, err := NewRefReader(dirs); err == nil {
+			if branch, err := rr.HeadBranch(); err == nil && branch != HeadFile {
+				opts.Branch = branch
+			}
+		}
+	}
+	return gitconfig.Load(opts)
+}
+
+// ConfigFile read the local config file of the repo, can be used to modify the config.
+//
+// NOTE: should call Invalidate(SectionRemotes, SectionHead) after saved the changes,
+// if AutoRefresh is not enabled.
+//
+// Usage:
+//
+//	f, err := repo.ConfigFile()
+//	err = f.Set("remote.origin.url", "git@github.com:gookit/gitw.git")
+//	err = f.Save()
+func (r *Repo) ConfigFile() (*gitconfig.File, error) {
+	dirs, err := r.GitDirs()
+	if err != nil {
+		return nil, err
+	}
+	return gitconfig.ReadFile(dirs.Path(ConfFile))
+}
+
+// build the remote infos from the config, same as the output of: git remote -v
+func (r *Repo) remotesFromConfig(cfg *gitconfig.Config) *repoRemotes {
+	names := make([]string, 0, 2)
+	rmp := make(map[string]RemoteInfos, 2)
+
+	for _, name := range cfg.Subsections("remote") {
+		urls := cfg.GetAll("remote." + name + ".url")
+		if len(urls) == 0 {
+			continue
+		}
+
+		// push URL: pushurl > url.<base>.pushInsteadOf > url.<base>.insteadOf
+		fetchURL, pushURL := cfg.InsteadOf(urls[0]), ""
+		if pushURLs := cfg.GetAll("remote." + name + ".pushurl"); len(pushURLs) > 0 {
+			pushURL = cfg.InsteadOf(pushURLs[len(pushURLs)-1])
+		} else if url, ok := cfg.PushInsteadOf(urls[len(urls)-1]); ok {
+			pushURL = url
+		} else {
+			pushURL = cfg.InsteadOf(urls[len(urls)-1])
+		}
+
+		rs := make(RemoteInfos, 2)
+		for typ, url := range map[string]string{RemoteTypeFetch: fetchURL, RemoteTypePush: pushURL} {
+			ri, err := NewRemoteInfo(name, url, typ)
+			if err != nil {
+				r.setErr(err)
+				continue
+			}
+			rs[typ] = ri
+		}
+
+		if len(rs) > 0 {
+			rmp[name] = rs
+			names = append(names, name)
+		}
+	}
+	return &repoRemotes{names: names, infos: rmp}
+}
+
+// Settings get the typed view of the well-known git config. see RepoSettings
+//
+// Will parse the config files by native parser on NativeConfig is true,
+// otherwise read from: git config --list -z
+func (r *Repo) Settings() (*RepoSettings, error) {
+	if r.cfg.NativeConfig {
+		if cfg, err := r.LoadConfig(); err == nil {
+			return NewRepoSettings(cfg), nil
+		}
+	}
+
+	out, err := r.gw.Config("--list", "-z").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := gitconfig.ParseList(out)
+	if err != nil {
+		return nil, err
+	}
+	return NewRepoSettings(cfg), nil
+}
+
+// DefaultBranch get the default branch name of the repo. the result will be cached.
+//
+// Detect order:
+//
+//   - RepoConfig.DefaultBranch, if it is not empty
+//   - the default branch of the DefaultRemote. see RemoteDefaultBranch(), without query the remote
+//   - the config init.defaultBranch
+//   - DefaultBranchName
+func (r *Repo) DefaultBranch() string {
+	if r.cfg.DefaultBranch != "" {
+		return r.cfg.DefaultBranch
+	}
+
+	return r.loadCacheStr(cacheDefaultBranch, func() (string, bool) {
+		if name := r.RemoteDefaultBranch(r.cfg.DefaultRemote); name != "" {
+			return name, true
+		}
+
+		if name := r.gw.Config("--get", "init.defaultBranch").SafeOutput(); name != "" {
+			return cmdr.FirstLine(name), true
+		}
+		return DefaultBranchName, true
+	})
+}
+
+// RemoteDefaultBranch get the default branch name of the remote. eg: main
+// If remote is empty, will use the DefaultRemote. returns empty on not found.
+//
+// Resolve from the local refs/remotes/<remote>/HEAD, it is set by clone or: git remote set-head <remote> -a
+// If not found and queryRemote=true, will query the remote by: git ls-remote --symref <remote> HEAD
+//
+// The found result will be cached.
+func (r *Repo) RemoteDefaultBranch(remote string, queryRemote ...bool) string {
+	remote = strutil.OrElse(remote, r.cfg.DefaultRemote)
+	query := len(queryRemote) > 0 && queryRemote[0]
+
+	return r.loadCacheStr(cacheRemoteDefBranch+":"+remote, func() (string, bool) {
+		remotePfx := RefsRemotesPrefix + remote + "/"
+		if target := r.remoteHeadTarget(remotePfx + HeadFile); strings.HasPrefix(target, remotePfx) {
+			return target[len(remotePfx):], true
+		}
+
+		if query {
+			name := r.queryRemoteHead(remote)
+			return name, name != ""
+		}
+		return "", false
+	})
+}
+
+// get the target of the remote HEAD symbolic ref. eg: refs/remotes/origin/main
+func (r *Repo) remoteHeadTarget(ref string) string {
+	if rr := r.nativeRefs(); rr != nil {
+		if ri, err := rr.Ref(ref); err == nil {
+			return ri.Target
+		}
+	}
+
+	// git symbolic-ref -q refs/remotes/origin/HEAD
+	return cmdr.FirstLine(r.gw.Cmd("symbolic-ref", "-q", ref).SafeOutput())
+}
+
+// query the HEAD branch of the remote.
+//
+// output eg:
+//
+//	ref: refs/heads/main	HEAD
+//	b6a8bc4...	HEAD
+func (r *Repo) queryRemoteHead(remote string) string {
+	out, err := r.gw.Cmd("ls-remote", "--symref", remote, HeadFile).Output()
+	if err != nil {
+		r.setErr(err)
+		return ""
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		target, ok := strings.CutPrefix(line, "ref: ")
+		if !ok {
+			continue
+		}
+
+		target, name, _ := strings.Cut(target, "\t")
+		if strings.TrimSpace(name) == HeadFile {
+			return strings.TrimPrefix(target, RefsHeadsPrefix)
+		}
+	}
+	return ""
+}
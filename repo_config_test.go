@@ -0,0 +1,129 @@
+package gitw_test
+
+import (
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/gitw/gitconfig"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestRepo_LoadConfig(t *testing.T) {
+	r := newTempRepo(t)
+	r.Git().WithConfigOverride("user.name", "inhere")
+
+	f, err := r.ConfigFile()
+	assert.NoErr(t, err)
+	assert.NoErr(t, f.Set("remote.origin.url", "https://github.com/gookit/gitw.git"))
+	assert.NoErr(t, f.Set("remote.upstream.url", "gh:inhere/gitw.git"))
+	assert.NoErr(t, f.Set("remote.upstream.pushurl", "https://github.com/inhere/gitw-push.git"))
+	assert.NoErr(t, f.Set(`url.git@github.com:.insteadOf`, "https://github.com/"))
+	assert.NoErr(t, f.Set(`url.https://github.com/.insteadOf`, "gh:"))
+	assert.NoErr(t, f.Save())
+
+	cfg, err := r.LoadConfig()
+	assert.NoErr(t, err)
+	e := cfg.Entry("user.name")
+	assert.Eq(t, "inhere", e.Value)
+	assert.Eq(t, gitconfig.ScopeCommand, e.Scope)
+	assert.Eq(t, gitconfig.ScopeLocal, cfg.Entry("remote.origin.url").Scope)
+	assert.Eq(t, "gh:inhere/gitw.git", cfg.Get("remote.upstream.url"))
+
+	// same as the git remote -v
+	nr := gitw.NewRepo(r.Dir()).WithConfigFn(func(cfg *gitw.RepoConfig) {
+		cfg.NativeConfig = true
+	})
+	assert.Eq(t, r.RemoteNames(), nr.RemoteNames())
+	assert.Eq(t, r.RemoteLines(), nr.RemoteLines())
+	assert.Eq(t, "git@github.com:gookit/gitw.git", nr.RemoteInfo("origin", gitw.RemoteTypeFetch).URL)
+	assert.Eq(t, r.RemoteInfo("upstream", gitw.RemoteTypeFetch).URL, nr.RemoteInfo("upstream", gitw.RemoteTypeFetch).URL)
+	assert.Eq(t, r.RemoteInfo("upstream", gitw.RemoteTypePush).URL, nr.RemoteInfo("upstream", gitw.RemoteTypePush).URL)
+	assert.NoErr(t, nr.Err())
+}
+
+func TestRepo_Settings(t *testing.T) {
+	r := newTempRepo(t)
+	f, err := r.ConfigFile()
+	assert.NoErr(t, err)
+	assert.NoErr(t, f.Set("user.name", "inhere"))
+	assert.NoErr(t, f.Set("user.signingKey", "ABC123"))
+	assert.NoErr(t, f.Set("commit.gpgSign", "yes"))
+	assert.NoErr(t, f.Set("pull.rebase", "m"))
+	assert.NoErr(t, f.Set("push.default", "current"))
+	assert.NoErr(t, f.Set("remote.origin.url", "https://github.com/gookit/gitw.git"))
+	assert.NoErr(t, f.Set("remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"))
+	assert.NoErr(t, f.Set("branch.main.remote", "origin"))
+	assert.NoErr(t, f.Set("branch.main.merge", "refs/heads/main"))
+	assert.NoErr(t, f.Save())
+
+	for _, native := range []bool{false, true} {
+		nr := gitw.NewRepo(r.Dir()).WithConfigFn(func(cfg *gitw.RepoConfig) {
+			cfg.NativeConfig = native
+		})
+
+		rs, err := nr.Settings()
+		assert.NoErr(t, err)
+		assert.Eq(t, "inhere", rs.UserName)
+		assert.Eq(t, "ABC123", rs.SigningKey)
+		assert.True(t, rs.GpgSign)
+		assert.Eq(t, "merges", rs.PullRebase)
+		assert.Eq(t, "current", rs.PushDefault)
+		assert.Eq(t, []string{"origin"}, rs.RemoteNames)
+
+		origin := rs.Remotes["origin"]
+		assert.Eq(t, "https://github.com/gookit/gitw.git", origin.URL())
+		assert.Len(t, origin.Fetch, 1)
+		assert.True(t, origin.Fetch[0].Force)
+		assert.Eq(t, "refs/remotes/origin/*", origin.Fetch[0].Dst)
+		assert.Eq(t, "+refs/heads/*:refs/remotes/origin/*", origin.Fetch[0].String())
+		assert.Eq(t, "origin/main", rs.Branches["main"].Upstream())
+	}
+}
+
+func TestRepo_DefaultBranch(t *testing.T) {
+	r := newTempRepo(t)
+	r.Git().WithConfigOverride("init.defaultBranch", "develop")
+	assert.Eq(t, "develop", r.DefaultBranch())
+
+	// detect from the remote HEAD
+	assert.NoErr(t, r.Cmd("update-ref", "refs/remotes/origin/main", "HEAD").Run())
+	assert.NoErr(t, r.Cmd("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/main").Run())
+	r.Invalidate(gitw.SectionRemotes)
+	assert.Eq(t, "main", r.DefaultBranch())
+
+	nr := gitw.NewRepo(r.Dir()).WithConfigFn(func(cfg *gitw.RepoConfig) {
+		cfg.NativeRefs = true
+	})
+	assert.Eq(t, "main", nr.DefaultBranch())
+
+	nr = gitw.NewRepo(r.Dir()).WithConfigFn(func(cfg *gitw.RepoConfig) {
+		cfg.DefaultBranch = "master"
+	})
+	assert.Eq(t, "master", nr.DefaultBranch())
+}
+
+func TestRepo_RemoteDefaultBranch(t *testing.T) {
+	up := newTempRepo(t)
+	r := newTempRepo(t)
+	assert.NoErr(t, r.Cmd("remote", "add", "up", up.Dir()).Run())
+
+	// not fetched
+	assert.Eq(t, "", r.RemoteDefaultBranch("up"))
+	assert.Eq(t, "main", r.RemoteDefaultBranch("up", true))
+	assert.Eq(t, "", r.RemoteDefaultBranch("not-exists", true))
+	assert.Err(t, r.Err())
+
+	// resolve from refs/remotes/up/HEAD
+	assert.NoErr(t, up.Cmd("branch", "-m", "main", "trunk").Run())
+	assert.NoErr(t, r.Cmd("fetch", "-q", "up").Run())
+	assert.NoErr(t, r.Cmd("remote", "set-head", "up", "-a").Run())
+	assert.Eq(t, "main", r.RemoteDefaultBranch("up"))
+	r.Invalidate(gitw.SectionRemotes)
+	assert.Eq(t, "trunk", r.RemoteDefaultBranch("up"))
+
+	// use for the DefaultBranch
+	nr := gitw.NewRepo(r.Dir()).WithConfigFn(func(cfg *gitw.RepoConfig) {
+		cfg.DefaultRemote = "up"
+	})
+	assert.Eq(t, "trunk", nr.DefaultBranch())
+}
@@ -1,9 +1,16 @@
 package gitw_test
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gookit/gitw"
+	"github.com/gookit/gitw/gitwtest"
 	"github.com/gookit/goutil/dump"
 	"github.com/gookit/goutil/sysutil"
 	"github.com/gookit/goutil/testutil/assert"
@@ -101,3 +108,188 @@ func TestRepo_TagByDescribe(t *testing.T) {
 	dump.P(tags)
 	assert.NotEmpty(t, tags)
 }
+
+func TestRepo_Info_fakeRunner(t *testing.T) {
+	fr := gitwtest.NewFakeRunner().
+		On("branch --show-current", "main\n").
+		On("tag -l --sort=-version:refname", "v0.3.1\nv0.3.0\n").
+		On("log -1 --format=%H", "3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61").
+		On("rev-parse --abbrev-ref @{u}", "origin/main\n").
+		On("remote -v", `origin	git@github.com:gookit/gitw.git (fetch)
+origin	git@github.com:gookit/gitw.git (push)
+`)
+
+	r := gitw.NewRepo("/path/to/gitw").WithRunner(fr)
+	info := r.Info()
+	assert.NoErr(t, r.Err())
+	assert.Eq(t, "gitw", info.Name)
+	assert.Eq(t, "gookit/gitw", info.Path)
+	assert.Eq(t, "main", info.Branch)
+	assert.Eq(t, "v0.3.1", info.Version)
+	assert.Eq(t, "3a6d3bb", info.LastHash)
+	assert.Eq(t, "origin/main", info.Upstream)
+	assert.Eq(t, "https://github.com/gookit/gitw", info.URL)
+	assert.Eq(t, "git@github.com:gookit/gitw.git", info.Remotes["origin"])
+}
+
+func TestRepo_BranchInfos_fakeRunner(t *testing.T) {
+	fr := gitwtest.NewFakeRunner().On("branch -v --all", `
+* main                     7r60d4f the message 002
+  fea/new_br001            73j824d the message 001
+  remotes/origin/main      7r60d4f the message 002
+`)
+
+	r := gitw.NewRepo("/path/to/gitw").WithRunner(fr)
+	assert.True(t, r.HasLocalBranch("fea/new_br001"))
+	assert.True(t, r.HasRemoteBranch("main", "origin"))
+	assert.Eq(t, "main", r.CurBranchInfo().Name)
+	assert.Len(t, r.BranchInfos().Locales(), 2)
+}
+
+func TestRepo_UpstreamPath(t *testing.T) {
+	up := newTempRepo(t)
+	r := newTempRepo(t)
+	assert.Eq(t, "", r.UpstreamPath())
+
+	assert.NoErr(t, r.Cmd("remote", "add", "up", up.Dir()).Run())
+	assert.NoErr(t, r.Cmd("fetch", "-q", "up").Run())
+	assert.NoErr(t, r.Cmd("branch", "-u", "up/main").Run())
+	assert.Eq(t, "up/main", r.UpstreamPath())
+	assert.Eq(t, "up", r.UpstreamRemote())
+}
+
+func TestRepo_WithContext(t *testing.T) {
+	r := newTempRepo(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cr := r.WithContext(ctx)
+	assert.Eq(t, "", cr.CurBranchName())
+	assert.True(t, errors.Is(cr.Err(), context.Canceled))
+
+	// not change the repo
+	assert.Nil(t, r.Git().Ctx)
+	assert.Eq(t, "main", r.CurBranchName())
+
+	// share the cache
+	tr := r.WithTimeout(time.Minute)
+	assert.Eq(t, time.Minute, tr.Git().Timeout)
+	assert.Eq(t, time.Duration(0), r.Git().Timeout)
+	assert.Eq(t, r.LastCommitID(), tr.LastCommitID())
+}
+
+func TestRepo_SetDryRun(t *testing.T) {
+	fr := gitwtest.NewFakeRunner().On("tag -l", "v0.3.1\nv0.3.0\n")
+
+	r := gitw.NewRepo("/path/to/gitw").WithRunner(fr).SetDryRun(true)
+	assert.Eq(t, []string{"v0.3.1", "v0.3.0"}, r.Tags())
+	assert.NoErr(t, r.SetUpstreamTo("origin", "main", "main"))
+
+	assert.Eq(t, []string{"LC_ALL=C git branch --set-upstream-to=origin/main main"}, r.DryRunPlan().Cmdlines())
+	assert.False(t, fr.Called("branch --set-upstream-to=origin/main main"))
+}
+
+func TestRepo_concurrentCache(t *testing.T) {
+	fr := gitwtest.NewFakeRunner().
+		On("log -1 --format=%H", "3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61\n").
+		On("status --porcelain=v2 --branch -z -u", "# branch.head main\x00# branch.upstream origin/main\x00").
+		On("branch -v --all", "* main 7r60d4f the message 002\n")
+
+	r := gitw.NewRepo("/path/to/gitw").WithRunner(fr)
+
+	var wg sync.WaitGroup
+	results := make([]string, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if r.StatusInfo() != nil {
+				results[i] = r.LastAbbrevID() + " " + r.CurBranchInfo().Name
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, ret := range results {
+		assert.Eq(t, "3a6d3bb main", ret)
+	}
+
+	countCalls := func(line string) (n int) {
+		for _, call := range fr.Calls() {
+			if call == line {
+				n++
+			}
+		}
+		return
+	}
+	assert.Eq(t, 1, countCalls("log -1 --format=%H"))
+	assert.Eq(t, 1, countCalls("status --porcelain=v2 --branch -z -u"))
+
+	r.Invalidate(gitw.SectionHead)
+	assert.Eq(t, "3a6d3bb", r.LastAbbrevID())
+	assert.NotNil(t, r.StatusInfo())
+	assert.Eq(t, 2, countCalls("log -1 --format=%H"))
+	assert.Eq(t, 1, countCalls("status --porcelain=v2 --branch -z -u"))
+
+	r.Refresh()
+	assert.NotNil(t, r.StatusInfo())
+	assert.Eq(t, 2, countCalls("status --porcelain=v2 --branch -z -u"))
+}
+
+func TestRepo_AutoRefresh(t *testing.T) {
+	dir := t.TempDir()
+	headFile := filepath.Join(dir, ".git", "HEAD")
+	indexFile := filepath.Join(dir, ".git", "index")
+	assert.NoErr(t, os.MkdirAll(filepath.Join(dir, ".git", "refs", "heads"), 0755))
+	assert.NoErr(t, os.MkdirAll(filepath.Join(dir, ".git", "objects"), 0755))
+	assert.NoErr(t, os.WriteFile(headFile, []byte("ref: refs/heads/main\n"), 0644))
+
+	fr := gitwtest.NewFakeRunner().
+		On("branch --show-current", "main\n").
+		On("status --porcelain=v2 --branch -z -u", "# branch.head main\x00")
+
+	r := gitw.NewRepo(dir).WithRunner(fr).WithConfigFn(func(cfg *gitw.RepoConfig) {
+		cfg.AutoRefresh = true
+	})
+	assert.Eq(t, "main", r.CurBranchName())
+	assert.Eq(t, "main", r.StatusInfo().Branch)
+	assert.Len(t, fr.Calls(), 2)
+
+	// no changes
+	assert.Eq(t, "main", r.CurBranchName())
+	assert.Len(t, fr.Calls(), 2)
+
+	// git add: only status will be reloaded
+	assert.NoErr(t, os.WriteFile(indexFile, []byte("DIRC"), 0644))
+	assert.Eq(t, "main", r.CurBranchName())
+	assert.Eq(t, "main", r.StatusInfo().Branch)
+	assert.Eq(t, []string{"branch --show-current", "status --porcelain=v2 --branch -z -u", "status --porcelain=v2 --branch -z -u"}, fr.Calls())
+
+	// git checkout
+	fr.On("branch --show-current", "dev\n").On("status --porcelain=v2 --branch -z -u", "# branch.head dev\x00")
+	assert.NoErr(t, os.WriteFile(headFile, []byte("ref: refs/heads/dev\n"), 0644))
+	assert.Eq(t, "dev", r.CurBranchName())
+	assert.Eq(t, "dev", r.StatusInfo().Branch)
+
+	// git fetch: the ref in the sub dir refs/remotes/origin is changed
+	remoteDir := filepath.Join(dir, ".git", "refs", "remotes", "origin")
+	assert.NoErr(t, os.MkdirAll(remoteDir, 0755))
+	fr.On("branch -v --all", "* dev 7r60d4f the message\n")
+	assert.Len(t, r.BranchInfos().Locales(), 1)
+	calls := len(fr.Calls())
+	assert.Len(t, r.BranchInfos().Locales(), 1)
+	assert.Len(t, fr.Calls(), calls)
+
+	assert.NoErr(t, os.WriteFile(filepath.Join(remoteDir, "dev"), []byte("7r60d4f\n"), 0644))
+	future := time.Now().Add(time.Minute)
+	assert.NoErr(t, os.Chtimes(remoteDir, future, future))
+	assert.Len(t, r.BranchInfos().Locales(), 1)
+	assert.Len(t, fr.Calls(), calls+1)
+
+	// the git dirs is cached
+	dirs1, err := r.GitDirs()
+	assert.NoErr(t, err)
+	dirs2, err := r.GitDirs()
+	assert.NoErr(t, err)
+	assert.True(t, dirs1 == dirs2)
+}
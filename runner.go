@@ -0,0 +1,93 @@
+package gitw
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// RunCmd the command data for Runner
+type RunCmd struct {
+	// Bin name. eg: git
+	Bin string
+	// Args full args for run, contains global flags and configs.
+	Args []string
+	// Dir the workdir for run
+	Dir string
+	// Env more environment variables. format: KEY=VALUE
+	Env []string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Runner interface for run git command.
+//
+// Can be used for fake the git command in tests. see package gitwtest
+type Runner interface {
+	// Run the command and write outputs to c.Stdout, c.Stderr.
+	//
+	// Returns the exit code and error. exit code is -1 on the process not started or killed.
+	Run(ctx context.Context, c *RunCmd) (exitCode int, err error)
+}
+
+// RunnerFunc wrap func as Runner
+type RunnerFunc func(ctx context.Context, c *RunCmd) (int, error)
+
+// Run the command
+func (fn RunnerFunc) Run(ctx context.Context, c *RunCmd) (int, error) {
+	return fn(ctx, c)
+}
+
+// DefaultRunner the default runner, run git command by os/exec.
+var DefaultRunner Runner = &ExecRunner{}
+
+// ExecRunner run git command by os/exec
+type ExecRunner struct{}
+
+// Run the command by exec.Cmd
+func (r *ExecRunner) Run(ctx context.Context, c *RunCmd) (int, error) {
+	cmd, restore := newExecCmd(ctx, c)
+	if restore != nil {
+		defer restore()
+	}
+
+	err := cmd.Run()
+	if err == nil {
+		return 0, nil
+	}
+
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		return ee.ExitCode(), err
+	}
+	return -1, err
+}
+
+// killWaitDelay wait for the I/O pipes closed after the process killed.
+const killWaitDelay = 3 * time.Second
+
+// create the exec.Cmd. restore is not nil on the command will be the foreground group of terminal,
+// should call it after the command exited.
+func newExecCmd(ctx context.Context, rc *RunCmd) (c *exec.Cmd, restore func()) {
+	c = exec.CommandContext(ctx, rc.Bin, rc.Args...)
+	if len(rc.Env) > 0 {
+		c.Env = append(os.Environ(), rc.Env...)
+	}
+
+	c.Dir = rc.Dir
+	c.Stdin = rc.Stdin
+	c.Stdout = rc.Stdout
+	c.Stderr = rc.Stderr
+
+	// can be canceled
+	if ctx.Done() != nil {
+		restore = setProcGroup(c)
+		c.WaitDelay = killWaitDelay
+	}
+	return c, restore
+}
@@ -0,0 +1,166 @@
+package gitw
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gookit/gitw/gitconfig"
+)
+
+// RepoSettings the typed view of the well-known git config of the repo. see Repo.Settings()
+type RepoSettings struct {
+	// UserName user.name
+	UserName string
+	// UserEmail user.email
+	UserEmail string
+	// SigningKey user.signingKey
+	SigningKey string
+	// GpgSign commit.gpgSign
+	GpgSign bool
+	// InitDefaultBranch init.defaultBranch
+	InitDefaultBranch string
+	// PullRebase pull.rebase, normalized value: "true", "false", "merges", "interactive". empty on not set
+	PullRebase string
+	// PushDefault push.default. eg: simple, current, upstream
+	PushDefault string
+	// HooksPath core.hooksPath
+	HooksPath string
+	// RemoteNames in the order of config
+	RemoteNames []string
+	// Remotes settings of remote.<name>.*
+	Remotes map[string]*RemoteSettings
+	// Branches settings of branch.<name>.*
+	Branches map[string]*BranchSettings
+	// Config the raw config for read other keys
+	Config *gitconfig.Config
+}
+
+// RemoteSettings the config of a remote. remote.<name>.*
+type RemoteSettings struct {
+	Name string
+	// URLs remote.<name>.url, the first one is used for fetch
+	URLs []string
+	// PushURLs remote.<name>.pushurl
+	PushURLs []string
+	// Fetch refspecs of remote.<name>.fetch
+	Fetch []*Refspec
+}
+
+// URL get the first fetch URL
+func (rs *RemoteSettings) URL() string {
+	if len(rs.URLs) > 0 {
+		return rs.URLs[0]
+	}
+	return ""
+}
+
+// BranchSettings the config of a local branch. branch.<name>.*
+type BranchSettings struct {
+	Name string
+	// Remote branch.<name>.remote. eg: origin
+	Remote string
+	// Merge branch.<name>.merge, the ref name on remote. eg: refs/heads/main
+	Merge string
+}
+
+// Upstream get the upstream short name. eg: origin/main. returns empty on not set
+func (bs *BranchSettings) Upstream() string {
+	if bs.Remote == "" || bs.Merge == "" {
+		return ""
+	}
+
+	branch := strings.TrimPrefix(bs.Merge, RefsHeadsPrefix)
+	if bs.Remote == "." {
+		return branch
+	}
+	return bs.Remote + "/" + branch
+}
+
+// Refspec of fetch or push. eg: +refs/heads/*:refs/remotes/origin/*
+type Refspec struct {
+	// Force update, the refspec has "+" prefix
+	Force bool
+	// Negative the refspec has "^" prefix, for exclude refs
+	Negative bool
+	Src      string
+	Dst      string
+}
+
+// ParseRefspec parse the refspec string. eg: +refs/heads/*:refs/remotes/origin/*
+func ParseRefspec(s string) *Refspec {
+	rs := &Refspec{}
+	if str, ok := strings.CutPrefix(s, "+"); ok {
+		s, rs.Force = str, true
+	} else if str, ok := strings.CutPrefix(s, "^"); ok {
+		s, rs.Negative = str, true
+	}
+
+	rs.Src, rs.Dst, _ = strings.Cut(s, ":")
+	return rs
+}
+
+// String get the refspec string
+func (rs *Refspec) String() string {
+	var s string
+	if rs.Force {
+		s = "+"
+	} else if rs.Negative {
+		s = "^"
+	}
+
+	if rs.Dst == "" {
+		return s + rs.Src
+	}
+	return s + rs.Src + ":" + rs.Dst
+}
+
+// NewRepoSettings create the settings from the git config
+func NewRepoSettings(cfg *gitconfig.Config) *RepoSettings {
+	rs := &RepoSettings{
+		UserName:          cfg.Get("user.name"),
+		UserEmail:         cfg.Get("user.email"),
+		SigningKey:        cfg.Get("user.signingKey"),
+		GpgSign:           cfg.Bool("commit.gpgSign", false),
+		InitDefaultBranch: cfg.Get("init.defaultBranch"),
+		PushDefault:       cfg.Get("push.default"),
+		HooksPath:         cfg.Get("core.hooksPath"),
+		Remotes:           make(map[string]*RemoteSettings),
+		Branches:          make(map[string]*BranchSettings),
+		Config:            cfg,
+	}
+
+	if e := cfg.Entry("pull.rebase"); e != nil {
+		switch strings.ToLower(e.Value) {
+		case "merges", "m":
+			rs.PullRebase = "merges"
+		case "interactive", "i":
+			rs.PullRebase = "interactive"
+		default:
+			rebase, _ := e.Bool()
+			rs.PullRebase = strconv.FormatBool(rebase)
+		}
+	}
+
+	for _, name := range cfg.Subsections("remote") {
+		remote := &RemoteSettings{
+			Name:     name,
+			URLs:     cfg.GetAll("remote." + name + ".url"),
+			PushURLs: cfg.GetAll("remote." + name + ".pushurl"),
+		}
+		for _, spec := range cfg.GetAll("remote." + name + ".fetch") {
+			remote.Fetch = append(remote.Fetch, ParseRefspec(spec))
+		}
+
+		rs.Remotes[name] = remote
+		rs.RemoteNames = append(rs.RemoteNames, name)
+	}
+
+	for _, name := range cfg.Subsections("branch") {
+		rs.Branches[name] = &BranchSettings{
+			Name:   name,
+			Remote: cfg.Get("branch." + name + ".remote"),
+			Merge:  cfg.Get("branch." + name + ".merge"),
+		}
+	}
+	return rs
+}
@@ -0,0 +1,269 @@
+package gitw
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// StashRef the ref name of the stash
+const StashRef = "refs/stash"
+
+// Stash entry info. by run: git stash list
+type Stash struct {
+	// Index of the stash list, 0 is the latest
+	Index int
+	// Ref name of the entry. eg: stash@{0}
+	Ref string
+	// Hash the commit ID of the stash
+	Hash string
+	// Branch name on create the stash. is "(no branch)" on detached HEAD
+	Branch string
+	// Message of the stash. eg: "WIP on main: 3a6d3bb message" will be "3a6d3bb message"
+	Message string
+	// Time the stash created
+	Time time.Time
+}
+
+// StashName build the stash ref name by index. eg: stash@{0}
+func StashName(index int) string {
+	return "stash@{" + strconv.Itoa(index) + "}"
+}
+
+// fields separator and the pretty format for parse stash list.
+const (
+	stashFieldSep = "\x1f"
+	stashFormat   = "%gd%x1f%H%x1f%cI%x1f%gs"
+	stashFields   = 4
+)
+
+// ParseStashList parse the output of: git stash list -z --format=%gd%x1f%H%x1f%cI%x1f%gs
+func ParseStashList(out string) ([]*Stash, error) {
+	var stashes []*Stash
+	for _, record := range strings.Split(out, "\x00") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		ss := strings.SplitN(record, stashFieldSep, stashFields)
+		if len(ss) != stashFields {
+			return nil, errorx.Rawf("invalid stash record: %q", record)
+		}
+
+		st := &Stash{Ref: ss[0], Hash: ss[1], Index: -1}
+		if idx, ok := strings.CutPrefix(ss[0], "stash@{"); ok {
+			st.Index, _ = strconv.Atoi(strings.TrimSuffix(idx, "}"))
+		}
+		if st.Index < 0 {
+			return nil, errorx.Rawf("invalid stash ref %q", ss[0])
+		}
+
+		var err error
+		if st.Time, err = time.Parse(time.RFC3339, ss[2]); err != nil {
+			return nil, errorx.Wrapf(err, "invalid date of stash %s", st.Ref)
+		}
+
+		// subject: "WIP on <branch>: <hash> <subject>" or "On <branch>: <message>"
+		subject := ss[3]
+		if s, ok := strings.CutPrefix(subject, "WIP on "); ok {
+			subject = s
+		} else {
+			subject = strings.TrimPrefix(subject, "On ")
+		}
+
+		if branch, msg, ok := strings.Cut(subject, ": "); ok {
+			st.Branch, st.Message = branch, msg
+		} else {
+			st.Message = subject
+		}
+		stashes = append(stashes, st)
+	}
+	return stashes, nil
+}
+
+// StashConflictError struct. returned on apply or pop stash has conflicts.
+//
+// Usage:
+//
+//	if errors.Is(err, gitw.ErrMergeConflict) {
+//		var se *gitw.StashConflictError
+//		errors.As(err, &se)
+//		fmt.Println(se.Paths)
+//	}
+type StashConflictError struct {
+	// Ref of the applied stash. eg: stash@{0}
+	Ref string
+	// Paths of the unmerged files
+	Paths []string
+	// Err the raw error of the git command
+	Err error
+}
+
+// Error string
+func (e *StashConflictError) Error() string {
+	return "apply " + e.Ref + " has conflicts in: " + strings.Join(e.Paths, ", ")
+}
+
+// Unwrap get raw error
+func (e *StashConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Is check the error is ErrMergeConflict
+func (e *StashConflictError) Is(target error) bool {
+	return target == ErrMergeConflict
+}
+
+// -------------------------------------------------
+// stash operations
+// -------------------------------------------------
+
+// Stashes get the stash list of the repo. the first one is the latest.
+func (r *Repo) Stashes() ([]*Stash, error) {
+	out, err := r.gw.Stash("list", "-z", "--format="+stashFormat).Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseStashList(out)
+}
+
+// StashPushOpts options for push stash. see Repo.StashPush
+type StashPushOpts struct {
+	// Message of the stash
+	Message string
+	// IncludeUntracked stash the untracked files too
+	IncludeUntracked bool
+	// All stash the untracked and ignored files too
+	All bool
+	// KeepIndex keep the staged changes in the index
+	KeepIndex bool
+	// Staged only stash the staged changes. require git 2.35+
+	Staged bool
+}
+
+// Args build the git stash push args. not contains the paths
+func (o *StashPushOpts) Args() []string {
+	var args []string
+	if o.Message != "" {
+		args = append(args, "--message", o.Message)
+	}
+	if o.All {
+		args = append(args, "--all")
+	} else if o.IncludeUntracked {
+		args = append(args, "--include-untracked")
+	}
+	if o.KeepIndex {
+		args = append(args, "--keep-index")
+	}
+	if o.Staged {
+		args = append(args, "--staged")
+	}
+	return args
+}
+
+// StashPush save the local changes to a new stash, paths is empty for all changes. opts can be nil.
+//
+// returns the created stash, will return nil stash on no local changes to save.
+//
+// Usage:
+//
+//	st, err := repo.StashPush(&gitw.StashPushOpts{Message: "before switch", IncludeUntracked: true})
+//	if err == nil && st != nil {
+//		defer repo.StashPop(st.Index, false)
+//	}
+func (r *Repo) StashPush(opts *StashPushOpts, paths ...string) (*Stash, error) {
+	before := r.stashHash()
+
+	gw := r.gw.Stash("push", "--quiet")
+	if opts != nil {
+		gw.AddArgs(opts.Args())
+	}
+	if err := gw.AddArg("--").AddArgs(paths).Run(); err != nil {
+		return nil, err
+	}
+	r.Invalidate(SectionStatus)
+
+	// no local changes to save
+	if after := r.stashHash(); after == "" || after == before {
+		return nil, nil
+	}
+
+	stashes, err := r.Stashes()
+	if err != nil {
+		return nil, err
+	}
+	if len(stashes) == 0 {
+		return nil, errorx.Raw("the created stash is not found")
+	}
+	return stashes[0], nil
+}
+
+// get the commit ID of the latest stash, is empty on no stash.
+func (r *Repo) stashHash() string {
+	return strings.TrimSpace(r.gw.RevParse("--quiet", "--verify", StashRef).SafeOutput())
+}
+
+// StashApply apply the stash by index to the worktree, will keep it in the stash list.
+//
+// restoreIndex: try to restore the staged changes too.
+// will return StashConflictError on has conflicts, check it by errors.Is(err, ErrMergeConflict)
+func (r *Repo) StashApply(index int, restoreIndex bool) error {
+	return r.applyStash("apply", index, restoreIndex)
+}
+
+// StashPop apply the stash by index and remove it from the stash list.
+//
+// the stash will be kept on has conflicts. see StashApply
+func (r *Repo) StashPop(index int, restoreIndex bool) error {
+	return r.applyStash("pop", index, restoreIndex)
+}
+
+func (r *Repo) applyStash(action string, index int, restoreIndex bool) error {
+	ref := StashName(index)
+	gw := r.gw.Stash(action)
+	if restoreIndex {
+		gw.AddArg("--index")
+	}
+
+	// NOTE: the conflict message is output to stdout
+	_, err := gw.AddArg(ref).Output()
+	r.Invalidate(SectionStatus)
+	if err == nil {
+		return nil
+	}
+
+	var ge *GitError
+	if errors.As(err, &ge) && ge.ExitCode == 1 {
+		paths, _ := r.gw.Diff("--name-only", "--diff-filter=U", "-z").Output()
+		if paths = strings.Trim(paths, "\x00"); paths != "" {
+			return &StashConflictError{Ref: ref, Paths: strings.Split(paths, "\x00"), Err: err}
+		}
+	}
+	return err
+}
+
+// StashDrop remove the stash by index from the stash list.
+func (r *Repo) StashDrop(index int) error {
+	return r.gw.Stash("drop", "--quiet", StashName(index)).Run()
+}
+
+// StashClear remove all the stash entries.
+func (r *Repo) StashClear() error {
+	return r.gw.Stash("clear").Run()
+}
+
+// StashShow get the changes of the stash by index as parsed diff files. see ParseDiff
+//
+// NOTE: the untracked files of the stash are not included.
+func (r *Repo) StashShow(index int) ([]*DiffFile, error) {
+	out, err := r.gw.Stash("show", "--patch", "--no-color", "--no-ext-diff", "-M",
+		"--src-prefix=a/", "--dst-prefix=b/", StashName(index)).Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiff(out)
+}
@@ -0,0 +1,96 @@
+package gitw_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParseStashList(t *testing.T) {
+	out := "stash@{0}\x1fde9b8fccc19994276650e10fffec20da287f543c\x1f2024-01-03T15:04:05+08:00\x1fOn main: my msg\x00" +
+		"stash@{1}\x1f3a6d3bb0c3e1a1f4a8c2d9a7e55c1e0b9f2d4c61\x1f2024-01-02T15:04:05+08:00\x1fWIP on fea/a: 3a6d3bb fix: some bug\x00"
+
+	stashes, err := gitw.ParseStashList(out)
+	assert.NoErr(t, err)
+	assert.Len(t, stashes, 2)
+
+	st := stashes[0]
+	assert.Eq(t, 0, st.Index)
+	assert.Eq(t, "stash@{0}", st.Ref)
+	assert.Eq(t, "main", st.Branch)
+	assert.Eq(t, "my msg", st.Message)
+	assert.Eq(t, int64(1704265445), st.Time.Unix())
+
+	st = stashes[1]
+	assert.Eq(t, 1, st.Index)
+	assert.Eq(t, "fea/a", st.Branch)
+	assert.Eq(t, "3a6d3bb fix: some bug", st.Message)
+
+	_, err = gitw.ParseStashList("stash@{0}\x1fabc")
+	assert.Err(t, err)
+}
+
+func TestRepo_Stash(t *testing.T) {
+	r := newTempRepo(t)
+
+	file := filepath.Join(r.Dir(), "a.txt")
+	assert.NoErr(t, os.WriteFile(file, []byte("a\n"), 0644))
+	assert.NoErr(t, r.Cmd("add", "a.txt").Run())
+	assert.NoErr(t, r.Cmd("commit", "-q", "-m", "add a.txt").Run())
+
+	// no changes
+	st, err := r.StashPush(nil)
+	assert.NoErr(t, err)
+	assert.Nil(t, st)
+
+	// push with untracked
+	assert.NoErr(t, os.WriteFile(file, []byte("b\n"), 0644))
+	assert.NoErr(t, os.WriteFile(filepath.Join(r.Dir(), "new.txt"), []byte("new\n"), 0644))
+	st, err = r.StashPush(&gitw.StashPushOpts{Message: "my msg", IncludeUntracked: true})
+	assert.NoErr(t, err)
+	assert.NotNil(t, st)
+	assert.Eq(t, "stash@{0}", st.Ref)
+	assert.Eq(t, "main", st.Branch)
+	assert.Eq(t, "my msg", st.Message)
+	assert.True(t, r.StatusInfo().IsCleaned())
+	assert.FileNotExists(t, filepath.Join(r.Dir(), "new.txt"))
+
+	files, err := r.StashShow(0)
+	assert.NoErr(t, err)
+	assert.Len(t, files, 1)
+	assert.Eq(t, "a.txt", files[0].Path())
+
+	// apply and keep
+	assert.NoErr(t, r.StashApply(0, false))
+	assert.FileExists(t, filepath.Join(r.Dir(), "new.txt"))
+	stashes, err := r.Stashes()
+	assert.NoErr(t, err)
+	assert.Len(t, stashes, 1)
+	assert.Eq(t, st.Hash, stashes[0].Hash)
+
+	// pop with conflict
+	assert.NoErr(t, os.Remove(filepath.Join(r.Dir(), "new.txt")))
+	assert.NoErr(t, os.WriteFile(file, []byte("c\n"), 0644))
+	assert.NoErr(t, r.Cmd("commit", "-q", "-am", "update a.txt").Run())
+	err = r.StashPop(0, false)
+	assert.Err(t, err)
+	assert.True(t, errors.Is(err, gitw.ErrMergeConflict))
+
+	var se *gitw.StashConflictError
+	assert.True(t, errors.As(err, &se))
+	assert.Eq(t, []string{"a.txt"}, se.Paths)
+	stashes, err = r.Stashes()
+	assert.NoErr(t, err)
+	assert.Len(t, stashes, 1)
+
+	assert.NoErr(t, r.StashDrop(0))
+	stashes, err = r.Stashes()
+	assert.NoErr(t, err)
+	assert.Empty(t, stashes)
+
+	assert.NoErr(t, r.StashClear())
+}
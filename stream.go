@@ -0,0 +1,137 @@
+package gitw
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// ErrStopStream can be returned by the Stream line handler, for stop read and not as error.
+var ErrStopStream = errorx.Raw("stop read git stream output")
+
+// StdoutPipe run the command in background and returns a reader for read the stdout.
+//
+// MUST call Close() after read. It will wait the git process exit, and return error on run failed.
+// If Close() before read to EOF, will kill the git process.
+//
+// Usage:
+//
+//	rc, err := gitw.New("log", "--format=%H").StdoutPipe()
+//	// ... read from rc
+//	err = rc.Close()
+func (gw *GitWrap) StdoutPipe() (io.ReadCloser, error) {
+	res := gw.beforeRun()
+	if res.DryRun {
+		gw.afterRun(res)
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+
+	ctx, cancel := context.WithCancel(gw.baseCtx())
+	pr, pw := io.Pipe()
+	sr := &streamReader{pr: pr, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		err := gw.doRun(ctx, res, pw, gw.Stderr)
+		sr.err = err
+		// nil err: reader will get io.EOF
+		_ = pw.CloseWithError(err)
+		close(sr.done)
+	}()
+	return sr, nil
+}
+
+// streamReader for read stdout of the running git command
+type streamReader struct {
+	pr     *io.PipeReader
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+	// the git run error
+	err error
+	// read finished(got io.EOF or error)
+	finished bool
+}
+
+// Read implements io.Reader
+func (r *streamReader) Read(p []byte) (int, error) {
+	n, err := r.pr.Read(p)
+	if err != nil {
+		r.finished = true
+	}
+	return n, err
+}
+
+// Close the reader and wait the git process exit.
+func (r *streamReader) Close() error {
+	r.once.Do(func() {
+		if !r.finished {
+			// stop read on half, kill the git process.
+			r.cancel()
+		}
+		_ = r.pr.Close()
+		<-r.done
+		r.cancel()
+	})
+
+	if !r.finished {
+		return nil
+	}
+	return r.err
+}
+
+// Stream run command and read stdout line by line. see StreamBy()
+//
+// Usage:
+//
+//	err := gitw.Log("--format=%H %s").Stream(func(line []byte) error {
+//		fmt.Println(string(line))
+//		return nil
+//	})
+func (gw *GitWrap) Stream(fn func(line []byte) error) error {
+	return gw.StreamBy('\n', fn)
+}
+
+// StreamBy run command and read stdout split by the sep char. eg: '\n', 0 for output by `-z`
+//
+// The line not contains the sep char, and it's only valid in the fn call, copy it if you need keep.
+// fn return ErrStopStream for stop read, other error will be returned.
+func (gw *GitWrap) StreamBy(sep byte, fn func(line []byte) error) error {
+	rc, err := gw.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	br := bufio.NewReaderSize(rc, 64*1024)
+	for {
+		line, rErr := br.ReadBytes(sep)
+		if len(line) > 0 {
+			line = bytes.TrimSuffix(line, []byte{sep})
+			if sep == '\n' {
+				line = bytes.TrimSuffix(line, []byte{'\r'})
+			}
+
+			if fErr := fn(line); fErr != nil {
+				_ = rc.Close()
+				if errors.Is(fErr, ErrStopStream) {
+					return nil
+				}
+				return fErr
+			}
+		}
+
+		if rErr != nil {
+			if rErr == io.EOF {
+				break
+			}
+			_ = rc.Close()
+			return rErr
+		}
+	}
+	return rc.Close()
+}
@@ -0,0 +1,283 @@
+package gitw
+
+import (
+	"errors"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// SubmodulesFile the submodules config file name in the worktree
+const SubmodulesFile = ".gitmodules"
+
+// Submodule info of the repo
+type Submodule struct {
+	// Name of the submodule. eg: submodule."name".path
+	Name string
+	// Path relative to the top level of the superproject worktree
+	Path string
+	URL  string
+	// Branch the remote branch to track. is empty on not set
+	Branch string
+	// RecordedSHA the commit ID recorded in the superproject index
+	RecordedSHA string
+	// HeadSHA the checked out commit ID. is empty on not initialized
+	HeadSHA string
+	// Initialized the submodule is initialized and checked out
+	Initialized bool
+	// OutOfDate the checked out commit does not match the recorded commit
+	OutOfDate bool
+	// Conflict the submodule has merge conflicts
+	Conflict bool
+	// Dirty the submodule has modified or untracked files
+	Dirty bool
+}
+
+// ParseSubmoduleConfig parse the output of: git config -f .gitmodules -z --get-regexp ^submodule\.
+//
+// returns the submodules in the order of config.
+func ParseSubmoduleConfig(out string) []*Submodule {
+	var sms []*Submodule
+	byName := make(map[string]*Submodule)
+
+	for _, item := range strings.Split(out, "\x00") {
+		// -z: key and value are split by newline
+		key, val, _ := strings.Cut(strings.TrimLeft(item, "\n"), "\n")
+		if !strings.HasPrefix(key, "submodule.") {
+			continue
+		}
+
+		// submodule.<name>.<var>, name maybe contains dot. skip the key without name. eg: submodule.x
+		idx := strings.LastIndexByte(key, '.')
+		if idx <= len("submodule.") {
+			continue
+		}
+		name, field := key[len("submodule."):idx], strings.ToLower(key[idx+1:])
+
+		sm := byName[name]
+		if sm == nil {
+			sm = &Submodule{Name: name}
+			byName[name] = sm
+			sms = append(sms, sm)
+		}
+
+		switch field {
+		case "path":
+			sm.Path = val
+		case "url":
+			sm.URL = val
+		case "branch":
+			sm.Branch = val
+		}
+	}
+	return sms
+}
+
+// parse the git submodule status output, returns map: path => status line info.
+//
+// line format: "[ +-U]<sha> <path>[ (<describe>)]"
+func parseSubmoduleStatus(out string) map[string]*Submodule {
+	sms := make(map[string]*Submodule)
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) < 3 {
+			continue
+		}
+
+		flag := line[0]
+		sha, path, ok := strings.Cut(line[1:], " ")
+		if !ok {
+			continue
+		}
+
+		// remove the describe info
+		if flag != '-' && strings.HasSuffix(path, ")") {
+			if idx := strings.LastIndex(path, " ("); idx > 0 {
+				path = path[:idx]
+			}
+		}
+
+		sm := &Submodule{Path: path, Initialized: flag != '-', OutOfDate: flag == '+', Conflict: flag == 'U'}
+		if sm.Initialized {
+			sm.HeadSHA = sha
+		}
+		sms[path] = sm
+	}
+	return sms
+}
+
+// Submodules get the submodules of the repo. it will not list the nested submodules,
+// can use SubmoduleRepo() to get them.
+//
+// Usage:
+//
+//	sms, err := repo.Submodules()
+//	for _, sm := range sms {
+//		fmt.Println(sm.Name, sm.Path, sm.HeadSHA, sm.Dirty)
+//	}
+func (r *Repo) Submodules() ([]*Submodule, error) {
+	dirs, err := r.GitDirs()
+	if err != nil {
+		return nil, err
+	}
+	if dirs.Bare {
+		return nil, errorx.Raw("can not list submodules in bare repository")
+	}
+
+	cfgFile := filepath.Join(dirs.WorkTree, SubmodulesFile)
+	out, err := r.gw.Config("-f", cfgFile, "-z", "--get-regexp", `^submodule\.`).Output()
+	if err != nil {
+		// exit code 1: the section or key is invalid. eg: no .gitmodules file
+		var ge *GitError
+		if errors.As(err, &ge) && ge.ExitCode == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	sms := ParseSubmoduleConfig(out)
+	if len(sms) == 0 {
+		return sms, nil
+	}
+
+	paths := make([]string, 0, len(sms))
+	for _, sm := range sms {
+		paths = append(paths, sm.Path)
+	}
+
+	// the paths are relative to the top level, so run the commands in the worktree dir.
+	wtDir := dirs.WorkTree
+
+	// the checked out commit
+	out, err = r.gw.Cmd("submodule", "status", "--").AddArgs(paths).WithWorkDir(wtDir).Output()
+	if err != nil {
+		return nil, err
+	}
+	states := parseSubmoduleStatus(out)
+
+	// the recorded commit in index. format: <mode> <sha> <stage>\t<path>
+	lines, err := r.gw.Cmd("ls-files", "-s", "--").AddArgs(paths).WithWorkDir(wtDir).OutputLines()
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := make(map[string]string, len(lines))
+	for _, line := range lines {
+		info, path, ok := strings.Cut(line, "\t")
+		if fields := strings.Fields(info); ok && len(fields) == 3 && fields[0] == "160000" {
+			recorded[path] = fields[1]
+		}
+	}
+
+	// the dirty state
+	out, err = r.gw.Status("--porcelain=v2", "-z", "--ignore-submodules=none", "--").AddArgs(paths).WithWorkDir(wtDir).Output()
+	if err != nil {
+		return nil, err
+	}
+	si, err := ParseStatusV2(out)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sm := range sms {
+		sm.RecordedSHA = recorded[sm.Path]
+		if st, ok := states[sm.Path]; ok {
+			sm.HeadSHA, sm.Initialized = st.HeadSHA, st.Initialized
+			sm.OutOfDate, sm.Conflict = st.OutOfDate, st.Conflict
+		}
+
+		for _, sf := range si.Files {
+			if sf.Path == sm.Path && sf.Submodule.IsSubmodule {
+				sm.Dirty = sf.Submodule.Modified || sf.Submodule.Untracked
+				break
+			}
+		}
+	}
+	return sms, nil
+}
+
+// SubmoduleRepo get the Repo of the submodule path. it will share the runner and hooks of current repo.
+func (r *Repo) SubmoduleRepo(path string) *Repo {
+	dir := filepath.Join(r.dir, path)
+	if dirs, err := r.GitDirs(); err == nil && dirs.WorkTree != "" {
+		dir = filepath.Join(dirs.WorkTree, path)
+	}
+
+	sub := NewRepo(dir)
+	sub.gw = r.gw.New().WithWorkDir(dir)
+	return sub
+}
+
+// SubmoduleUpdateOpts options for update submodules. see Repo.UpdateSubmodules
+type SubmoduleUpdateOpts struct {
+	// Init the not initialized submodules before update
+	Init bool
+	// Recursive update the nested submodules
+	Recursive bool
+	// Remote update to the latest commit of the remote tracking branch
+	Remote bool
+	// Force checkout, discard the local changes
+	Force bool
+	// Jobs number of submodules fetched at the same time
+	Jobs int
+	// Depth create a shallow clone with the history depth
+	Depth int
+}
+
+// Args build the git submodule update args. not contains the paths
+func (o *SubmoduleUpdateOpts) Args() []string {
+	var args []string
+	if o.Init {
+		args = append(args, "--init")
+	}
+	if o.Recursive {
+		args = append(args, "--recursive")
+	}
+	if o.Remote {
+		args = append(args, "--remote")
+	}
+	if o.Force {
+		args = append(args, "--force")
+	}
+	if o.Jobs > 0 {
+		args = append(args, "--jobs", strconv.Itoa(o.Jobs))
+	}
+	if o.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(o.Depth))
+	}
+	return args
+}
+
+// InitSubmodules init the submodules, paths is empty for all submodules.
+func (r *Repo) InitSubmodules(paths ...string) error {
+	return r.gw.Cmd("submodule", "init", "--").AddArgs(paths).Run()
+}
+
+// UpdateSubmodules update the submodules, paths is empty for all submodules. opts can be nil.
+//
+// Usage:
+//
+//	err := repo.UpdateSubmodules(&gitw.SubmoduleUpdateOpts{Init: true, Recursive: true})
+func (r *Repo) UpdateSubmodules(opts *SubmoduleUpdateOpts, paths ...string) error {
+	gw := r.gw.Cmd("submodule", "update")
+	if opts != nil {
+		gw.AddArgs(opts.Args())
+	}
+
+	if err := gw.AddArg("--").AddArgs(paths).Run(); err != nil {
+		return err
+	}
+
+	r.Invalidate(SectionStatus)
+	return nil
+}
+
+// SyncSubmodules sync the remote URL of the submodules from .gitmodules, paths is empty for all submodules.
+func (r *Repo) SyncSubmodules(recursive bool, paths ...string) error {
+	gw := r.gw.Cmd("submodule", "sync")
+	if recursive {
+		gw.AddArg("--recursive")
+	}
+	return gw.AddArg("--").AddArgs(paths).Run()
+}
@@ -0,0 +1,98 @@
+package gitw_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParseSubmoduleConfig(t *testing.T) {
+	out := "submodule.libs/a.path\nlibs/a\x00submodule.libs/a.url\nhttps://github.com/gookit/a.git\x00" +
+		"submodule.v1.0.b.path\nlibs/b\x00submodule.v1.0.b.branch\nmain\x00submodule.v1.0.b.URL\n../b.git\x00" +
+		// no name part. from: [submodule] x = 1
+		"submodule.x\n1\x00submodule..path\nlibs/c\x00"
+
+	sms := gitw.ParseSubmoduleConfig(out)
+	assert.Len(t, sms, 2)
+	assert.Eq(t, gitw.Submodule{Name: "libs/a", Path: "libs/a", URL: "https://github.com/gookit/a.git"}, *sms[0])
+	assert.Eq(t, "v1.0.b", sms[1].Name)
+	assert.Eq(t, "main", sms[1].Branch)
+	assert.Eq(t, "../b.git", sms[1].URL)
+}
+
+func TestRepo_Submodules(t *testing.T) {
+	lib := newTempRepo(t)
+	r := newTempRepo(t)
+	// allow clone submodule from the local path
+	for _, gw := range []*gitw.GitWrap{r.Git(), lib.Git()} {
+		gw.WithEnv("GIT_CONFIG_COUNT", "1").
+			WithEnv("GIT_CONFIG_KEY_0", "protocol.file.allow").
+			WithEnv("GIT_CONFIG_VALUE_0", "always")
+	}
+
+	sms, err := r.Submodules()
+	assert.NoErr(t, err)
+	assert.Empty(t, sms)
+
+	assert.NoErr(t, r.Cmd("submodule", "add", "-q", lib.Dir(), "libs/lib").Run())
+	sms, err = r.Submodules()
+	assert.NoErr(t, err)
+	assert.Len(t, sms, 1)
+
+	sm := sms[0]
+	assert.Eq(t, "libs/lib", sm.Name)
+	assert.Eq(t, "libs/lib", sm.Path)
+	assert.Eq(t, lib.Dir(), sm.URL)
+	assert.True(t, sm.Initialized)
+	assert.Eq(t, lib.LastCommitID(), sm.HeadSHA)
+	assert.Eq(t, sm.HeadSHA, sm.RecordedSHA)
+	assert.False(t, sm.OutOfDate)
+	assert.False(t, sm.Dirty)
+
+	// run in the sub dir of the worktree
+	dr := gitw.NewRepo(filepath.Join(r.Dir(), "libs"))
+	sms, err = dr.Submodules()
+	assert.NoErr(t, err)
+	assert.Len(t, sms, 1)
+	assert.Eq(t, sm.HeadSHA, sms[0].HeadSHA)
+	assert.Eq(t, sm.RecordedSHA, sms[0].RecordedSHA)
+	assert.True(t, sms[0].Initialized)
+
+	// dirty
+	sr := r.SubmoduleRepo("libs/lib")
+	assert.Eq(t, lib.LastCommitID(), sr.LastCommitID())
+	assert.NoErr(t, os.WriteFile(filepath.Join(sr.Dir(), "new.txt"), []byte("new"), 0644))
+	sms, err = r.Submodules()
+	assert.NoErr(t, err)
+	assert.True(t, sms[0].Dirty)
+
+	// out of date
+	assert.NoErr(t, sr.Cmd("checkout", "-q", "HEAD~1").Run())
+	sms, err = r.Submodules()
+	assert.NoErr(t, err)
+	assert.True(t, sms[0].OutOfDate)
+	assert.NotEq(t, sms[0].HeadSHA, sms[0].RecordedSHA)
+
+	assert.NoErr(t, r.UpdateSubmodules(nil))
+	assert.NoErr(t, r.SyncSubmodules(true))
+	sms, err = r.Submodules()
+	assert.NoErr(t, err)
+	assert.False(t, sms[0].OutOfDate)
+
+	// not initialized
+	assert.NoErr(t, r.Cmd("submodule", "deinit", "-q", "-f", "libs/lib").Run())
+	sms, err = r.Submodules()
+	assert.NoErr(t, err)
+	assert.False(t, sms[0].Initialized)
+	assert.Eq(t, "", sms[0].HeadSHA)
+	assert.NotEmpty(t, sms[0].RecordedSHA)
+
+	assert.NoErr(t, r.InitSubmodules())
+	assert.NoErr(t, r.UpdateSubmodules(&gitw.SubmoduleUpdateOpts{Recursive: true, Jobs: 2}))
+	sms, err = r.Submodules()
+	assert.NoErr(t, err)
+	assert.True(t, sms[0].Initialized)
+}
@@ -0,0 +1,189 @@
+package gitw
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/strutil"
+	"github.com/gookit/goutil/sysutil/cmdr"
+)
+
+// ErrNoMergeBase error for the commits has no common ancestor.
+var ErrNoMergeBase = errorx.Raw("no merge base found")
+
+// AheadBehind count the commits of a not in b(ahead), and b not in a(behind).
+//
+// CMD:
+//
+//	git rev-list --left-right --count --end-of-options a...b
+//
+// Usage:
+//
+//	ahead, behind, err := repo.AheadBehind("fea/new", "origin/main")
+func (r *Repo) AheadBehind(a, b string) (ahead, behind int, err error) {
+	out, err := r.gw.RevList("--left-right", "--count", "--end-of-options", a+"..."+b).Output()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// output eg: "1\t2"
+	ss := strings.Fields(out)
+	if len(ss) != 2 {
+		return 0, 0, errorx.Rawf("invalid rev-list count output: %q", out)
+	}
+
+	if ahead, err = strconv.Atoi(ss[0]); err == nil {
+		behind, err = strconv.Atoi(ss[1])
+	}
+	return ahead, behind, err
+}
+
+// MergeBase find the best common ancestor commit of a and others.
+// returns ErrNoMergeBase on the commits has no common history.
+//
+// CMD:
+//
+//	git merge-base a b ...
+func (r *Repo) MergeBase(a string, others ...string) (string, error) {
+	if len(others) == 0 {
+		return "", errorx.Raw("merge base requires at least two commits")
+	}
+
+	out, err := r.gw.Cmd("merge-base", a).AddArgs(others).Output()
+	if err != nil {
+		// exit code 1 and no output: no common ancestor
+		var ge *GitError
+		if errors.As(err, &ge) && ge.ExitCode == 1 && ge.Stderr == "" {
+			return "", ErrNoMergeBase
+		}
+		return "", err
+	}
+	return cmdr.FirstLine(out), nil
+}
+
+// IsMerged check the branch has been merged into the target. same as branch in: git branch --merged <into>
+// If into is empty, will use the HEAD.
+//
+// CMD:
+//
+//	git merge-base --is-ancestor <branch> <into>
+func (r *Repo) IsMerged(branch, into string) (bool, error) {
+	into = strutil.OrElse(into, HeadFile)
+
+	err := r.gw.Cmd("merge-base", "--is-ancestor", branch, into).Run()
+	if err != nil {
+		// exit code 1: is not an ancestor
+		var ge *GitError
+		if errors.As(err, &ge) && ge.ExitCode == 1 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// fields: HEAD, refname, objectname, subject, upstream, track, worktreepath
+const branchTrackFormat = "%(HEAD)%00%(refname)%00%(objectname:short)%00%(contents:subject)%00" +
+	"%(upstream:short)%00%(upstream:track,nobracket)%00%(worktreepath)"
+
+// BranchTracks get all local branches with the upstream tracking info(Upstream, Ahead, Behind, Gone).
+// the result will be cached, reload after Invalidate(SectionBranches).
+//
+// CMD:
+//
+//	git for-each-ref --format=<format> refs/heads
+//
+// Usage:
+//
+//	for _, bi := range repo.BranchTracks() {
+//		if bi.Gone || bi.Behind > 0 { ... }
+//	}
+func (r *Repo) BranchTracks() []*BranchInfo {
+	val := r.loadCache(cacheBranchTracks, func() (any, bool) {
+		out, err := r.gw.Cmd("for-each-ref", "--format="+branchTrackFormat, RefsHeadsPrefix).Output()
+		if err != nil {
+			r.setErr(err)
+			return []*BranchInfo{}, false
+		}
+
+		list, err := ParseBranchTracks(out)
+		if err != nil {
+			r.setErr(err)
+		}
+		return list, true
+	})
+	return val.([]*BranchInfo)
+}
+
+// BranchTrack get the tracking info of a local branch. returns nil on not found.
+func (r *Repo) BranchTrack(branch string) *BranchInfo {
+	for _, bi := range r.BranchTracks() {
+		if bi.Name == branch {
+			return bi
+		}
+	}
+	return nil
+}
+
+// ParseBranchTracks parse the output of for-each-ref by branchTrackFormat.
+//
+// line eg(fields split by NUL): "*", "refs/heads/main", "7r60d4f", "subject", "origin/main", "ahead 1, behind 2", "/path/to/wt"
+func ParseBranchTracks(out string) ([]*BranchInfo, error) {
+	var list []*BranchInfo
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimRight(line, "\r"); line == "" {
+			continue
+		}
+
+		ss := strings.Split(line, "\x00")
+		if len(ss) != 7 {
+			return list, errorx.Rawf("invalid branch track line: %q", line)
+		}
+
+		bi := &BranchInfo{
+			Current:  ss[0] == "*",
+			Hash:     ss[2],
+			HashMsg:  ss[3],
+			Upstream: ss[4],
+		}
+		bi.SetName(strings.TrimPrefix(ss[1], RefsHeadsPrefix))
+		bi.Worktree = !bi.Current && ss[6] != ""
+
+		if err := bi.parseTrack(ss[5]); err != nil {
+			return list, err
+		}
+		list = append(list, bi)
+	}
+	return list, nil
+}
+
+// parse the upstream track. eg: "ahead 1, behind 2", "gone"
+func (b *BranchInfo) parseTrack(track string) (err error) {
+	if track == "gone" {
+		b.Gone = true
+		return nil
+	}
+
+	for _, part := range strings.Split(track, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		key, num, _ := strings.Cut(part, " ")
+		switch key {
+		case "ahead":
+			b.Ahead, err = strconv.Atoi(num)
+		case "behind":
+			b.Behind, err = strconv.Atoi(num)
+		default:
+			err = errorx.Rawf("invalid upstream track: %q", track)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package gitw_test
+
+import (
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestRepo_AheadBehind(t *testing.T) {
+	r := newTempRepo(t)
+	run := func(cmd string, args ...string) {
+		assert.NoErr(t, r.Cmd(cmd, args...).Run())
+	}
+
+	run("checkout", "-q", "-b", "fea/one")
+	run("commit", "-q", "--allow-empty", "-m", "feat: fea commit 1")
+	run("commit", "-q", "--allow-empty", "-m", "feat: fea commit 2")
+	run("checkout", "-q", "main")
+	run("commit", "-q", "--allow-empty", "-m", "fix: main commit")
+
+	ahead, behind, err := r.AheadBehind("fea/one", "main")
+	assert.NoErr(t, err)
+	assert.Eq(t, 2, ahead)
+	assert.Eq(t, 1, behind)
+	_, _, err = r.AheadBehind("not-exists", "main")
+	assert.Err(t, err)
+	_, _, err = r.AheadBehind("--all", "main")
+	assert.Err(t, err)
+
+	base, err := r.MergeBase("fea/one", "main")
+	assert.NoErr(t, err)
+	assert.Eq(t, r.Cmd("rev-parse", "main~1").SafeOutput(), base+"\n")
+
+	// no common history
+	run("checkout", "-q", "--orphan", "orphan")
+	run("commit", "-q", "--allow-empty", "-m", "orphan commit")
+	_, err = r.MergeBase("orphan", "main")
+	assert.ErrIs(t, err, gitw.ErrNoMergeBase)
+	_, err = r.MergeBase("main")
+	assert.Err(t, err)
+
+	ok, err := r.IsMerged("main~1", "fea/one")
+	assert.NoErr(t, err)
+	assert.True(t, ok)
+	ok, err = r.IsMerged("fea/one", "main")
+	assert.NoErr(t, err)
+	assert.False(t, ok)
+	ok, err = r.IsMerged("orphan", "")
+	assert.NoErr(t, err)
+	assert.True(t, ok)
+	_, err = r.IsMerged("not-exists", "main")
+	assert.Err(t, err)
+}
+
+func TestRepo_BranchTracks(t *testing.T) {
+	up := newTempRepo(t)
+	r := newTempRepo(t)
+	run := func(gr *gitw.Repo, cmd string, args ...string) {
+		assert.NoErr(t, gr.Cmd(cmd, args...).Run())
+	}
+
+	run(up, "branch", "old")
+	run(up, "commit", "-q", "--allow-empty", "-m", "fix: up commit")
+	run(r, "remote", "add", "up", up.Dir())
+	run(r, "fetch", "-q", "up")
+
+	run(r, "branch", "--set-upstream-to=up/main", "main")
+	run(r, "commit", "-q", "--allow-empty", "-m", "feat: local commit")
+	run(r, "branch", "--track", "old", "up/old")
+	run(r, "branch", "local")
+
+	// delete the remote branch
+	run(up, "branch", "-D", "old")
+	run(r, "fetch", "-q", "--prune", "up")
+
+	list := r.BranchTracks()
+	assert.NoErr(t, r.Err())
+	assert.Len(t, list, 3)
+
+	main := r.BranchTrack("main")
+	assert.True(t, main.Current)
+	assert.Eq(t, "up/main", main.Upstream)
+	// the base commits are same, by the fixed commit dates
+	assert.Eq(t, 1, main.Ahead)
+	assert.Eq(t, 1, main.Behind)
+	assert.NotEmpty(t, main.Hash)
+	assert.Eq(t, "feat: local commit", main.HashMsg)
+
+	old := r.BranchTrack("old")
+	assert.True(t, old.Gone)
+	assert.Eq(t, "up/old", old.Upstream)
+
+	local := r.BranchTrack("local")
+	assert.Eq(t, "", local.Upstream)
+	assert.False(t, local.Gone)
+	assert.Eq(t, 0, local.Ahead)
+	assert.Nil(t, r.BranchTrack("not-exists"))
+}
@@ -39,9 +39,9 @@ func IsGitDir(dir string) bool {
 	return New("--git-dir="+dir, "rev-parse", "--git-dir").Success()
 }
 
-// HasDotGitDir in the path
+// HasDotGitDir in the path. the ".git" can be a dir or gitfile(in linked worktree and submodule)
 func HasDotGitDir(path string) bool {
-	return fsutil.IsDir(path + "/" + GitDir)
+	return fsutil.PathExists(path + "/" + GitDir)
 }
 
 var editorCmd string
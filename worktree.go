@@ -0,0 +1,218 @@
+package gitw
+
+import (
+	"strings"
+
+	"github.com/gookit/goutil/errorx"
+)
+
+// Worktree info of the repo. by run: git worktree list --porcelain
+type Worktree struct {
+	// Path of the worktree
+	Path string
+	// Head commit ID of the worktree. is empty on bare
+	Head string
+	// Branch short name. eg: main. is empty on detached or bare
+	Branch string
+	// Main is the main worktree. it is the first one of the list
+	Main bool
+	Bare bool
+	// Detached HEAD
+	Detached bool
+	Locked   bool
+	// LockReason maybe empty on locked
+	LockReason string
+	// Prunable the worktree can be pruned. eg: the path is not exists
+	Prunable    bool
+	PruneReason string
+}
+
+// ParseWorktrees parse the output of: git worktree list --porcelain
+//
+// Output eg:
+//
+//	worktree /path/to/repo
+//	HEAD 1a2b3c...
+//	branch refs/heads/main
+//
+//	worktree /path/to/feat
+//	HEAD 4d5e6f...
+//	detached
+//	locked some reason
+func ParseWorktrees(out string) ([]*Worktree, error) {
+	var wts []*Worktree
+	var wt *Worktree
+
+	for _, line := range strings.Split(strings.ReplaceAll(out, "\r\n", "\n"), "\n") {
+		if line == "" {
+			wt = nil
+			continue
+		}
+
+		key, val, _ := strings.Cut(line, " ")
+		if key == "worktree" {
+			wt = &Worktree{Path: val, Main: len(wts) == 0}
+			wts = append(wts, wt)
+			continue
+		}
+
+		if wt == nil {
+			return nil, errorx.Rawf("invalid worktree line %q, missing the worktree path", line)
+		}
+
+		switch key {
+		case "HEAD":
+			wt.Head = val
+		case "branch":
+			wt.Branch = strings.TrimPrefix(val, "refs/heads/")
+		case "bare":
+			wt.Bare = true
+		case "detached":
+			wt.Detached = true
+		case "locked":
+			wt.Locked, wt.LockReason = true, val
+		case "prunable":
+			wt.Prunable, wt.PruneReason = true, val
+		}
+	}
+	return wts, nil
+}
+
+// Worktrees get all worktrees of the repo, the first one is the main worktree.
+func (r *Repo) Worktrees() ([]*Worktree, error) {
+	out, err := r.gw.Worktree("list", "--porcelain").Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseWorktrees(out)
+}
+
+// WorktreeAddOpts options for add worktree. see Repo.AddWorktree
+type WorktreeAddOpts struct {
+	// Branch create a new branch for the worktree. use -b, or -B on ForceBranch
+	Branch string
+	// ForceBranch reset the Branch to commitish if it exists.
+	ForceBranch bool
+	// Detach HEAD in the new worktree
+	Detach bool
+	// Force add even if the branch is checked out by another worktree
+	Force bool
+	// NoCheckout not checkout the files
+	NoCheckout bool
+	// Lock the worktree after add. can with LockReason
+	Lock       bool
+	LockReason string
+}
+
+// Args build the git worktree add args. not contains the path and commitish
+func (o *WorktreeAddOpts) Args() []string {
+	var args []string
+	if o.Branch != "" {
+		if o.ForceBranch {
+			args = append(args, "-B", o.Branch)
+		} else {
+			args = append(args, "-b", o.Branch)
+		}
+	}
+
+	if o.Detach {
+		args = append(args, "--detach")
+	}
+	if o.Force {
+		args = append(args, "--force")
+	}
+	if o.NoCheckout {
+		args = append(args, "--no-checkout")
+	}
+	if o.Lock {
+		args = append(args, "--lock")
+		if o.LockReason != "" {
+			args = append(args, "--reason", o.LockReason)
+		}
+	}
+	return args
+}
+
+// AddWorktree add a new worktree at path. commitish and opts can be empty.
+//
+// Usage:
+//
+//	err := repo.AddWorktree("../feat-x", "main", &gitw.WorktreeAddOpts{Branch: "feat-x"})
+func (r *Repo) AddWorktree(path, commitish string, opts *WorktreeAddOpts) error {
+	args := []string{"add"}
+	if opts != nil {
+		args = append(args, opts.Args()...)
+	}
+
+	args = append(args, path)
+	if commitish != "" {
+		args = append(args, commitish)
+	}
+
+	if err := r.gw.Worktree(args...).Run(); err != nil {
+		return err
+	}
+
+	if opts != nil && opts.Branch != "" {
+		r.Invalidate(SectionBranches)
+	}
+	return nil
+}
+
+// RemoveWorktree remove the worktree. force for remove the worktree has changes.
+func (r *Repo) RemoveWorktree(path string, force bool) error {
+	args := []string{"remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	if err := r.gw.Worktree(append(args, path)...).Run(); err != nil {
+		return err
+	}
+
+	// the branch is no longer checked out in a worktree
+	r.Invalidate(SectionBranches)
+	return nil
+}
+
+// LockWorktree lock the worktree, prevent it from being pruned. reason can be empty.
+func (r *Repo) LockWorktree(path, reason string) error {
+	args := []string{"lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	return r.gw.Worktree(append(args, path)...).Run()
+}
+
+// UnlockWorktree unlock the worktree
+func (r *Repo) UnlockWorktree(path string) error {
+	return r.gw.Worktree("unlock", path).Run()
+}
+
+// WorktreePruneOpts options for prune worktrees. see Repo.PruneWorktrees
+type WorktreePruneOpts struct {
+	// DryRun only report what would be removed
+	DryRun bool
+	// Expire only prune the worktrees older than the time. eg: "3.days.ago", "now"
+	Expire string
+}
+
+// PruneWorktrees prune the worktree information of the not exists worktrees.
+// returns the output messages of git, it is useful on DryRun.
+func (r *Repo) PruneWorktrees(opts *WorktreePruneOpts) (string, error) {
+	args := []string{"prune", "--verbose"}
+	if opts != nil {
+		if opts.DryRun {
+			args = append(args, "--dry-run")
+		}
+		if opts.Expire != "" {
+			args = append(args, "--expire", opts.Expire)
+		}
+	}
+
+	// the prune messages are written to stderr
+	out, err := r.gw.Worktree(args...).CombinedOutput()
+	if err == nil && (opts == nil || !opts.DryRun) {
+		r.Invalidate(SectionBranches)
+	}
+	return strings.TrimSpace(out), err
+}
@@ -0,0 +1,107 @@
+package gitw_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gookit/gitw"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParseWorktrees(t *testing.T) {
+	out := `worktree /path/to/repo
+HEAD 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b
+branch refs/heads/main
+
+worktree /path/to/feat
+HEAD 4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1a2b3c
+detached
+locked on usb disk
+
+worktree /path/to/gone
+HEAD 4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1a2b3c
+branch refs/heads/feat/gone
+prunable gitdir file points to non-existent location
+`
+	wts, err := gitw.ParseWorktrees(out)
+	assert.NoErr(t, err)
+	assert.Len(t, wts, 3)
+
+	assert.True(t, wts[0].Main)
+	assert.Eq(t, "main", wts[0].Branch)
+	assert.Eq(t, "/path/to/repo", wts[0].Path)
+
+	assert.False(t, wts[1].Main)
+	assert.True(t, wts[1].Detached)
+	assert.True(t, wts[1].Locked)
+	assert.Eq(t, "on usb disk", wts[1].LockReason)
+
+	assert.Eq(t, "feat/gone", wts[2].Branch)
+	assert.True(t, wts[2].Prunable)
+
+	wts, err = gitw.ParseWorktrees("worktree /path/to/bare.git\nbare\n")
+	assert.NoErr(t, err)
+	assert.True(t, wts[0].Bare)
+
+	_, err = gitw.ParseWorktrees("HEAD 1a2b3c\n")
+	assert.Err(t, err)
+}
+
+func TestRepo_Worktrees(t *testing.T) {
+	r := newTempRepo(t)
+	wtPath := filepath.Join(t.TempDir(), "feat-x")
+
+	err := r.AddWorktree(wtPath, "main", &gitw.WorktreeAddOpts{Branch: "feat-x", Lock: true, LockReason: "testing"})
+	assert.NoErr(t, err)
+	assert.True(t, r.HasLocalBranch("feat-x"))
+
+	wts, err := r.Worktrees()
+	assert.NoErr(t, err)
+	assert.Len(t, wts, 2)
+	assert.Eq(t, "main", wts[0].Branch)
+	assert.Eq(t, "feat-x", wts[1].Branch)
+	assert.True(t, wts[1].Locked)
+	assert.Eq(t, "testing", wts[1].LockReason)
+
+	// the .git is a file in the linked worktree
+	wr := gitw.NewRepo(wtPath)
+	assert.True(t, wr.IsGitRepo())
+	assert.StrContains(t, filepath.ToSlash(wr.Git().GitDir()), ".git/worktrees/feat-x")
+	assert.Eq(t, "feat-x", wr.CurBranchName())
+
+	// the branch is checked out in the worktree
+	assert.True(t, r.BranchTrack("feat-x").Worktree)
+
+	// locked, can't remove
+	assert.Err(t, r.RemoveWorktree(wtPath, false))
+	assert.NoErr(t, r.UnlockWorktree(wtPath))
+	assert.NoErr(t, r.LockWorktree(wtPath, ""))
+	assert.NoErr(t, r.UnlockWorktree(wtPath))
+
+	// has changes, need force
+	assert.NoErr(t, os.WriteFile(filepath.Join(wtPath, "new.txt"), []byte("hi"), 0644))
+	assert.Err(t, r.RemoveWorktree(wtPath, false))
+	assert.NoErr(t, r.RemoveWorktree(wtPath, true))
+
+	wts, err = r.Worktrees()
+	assert.NoErr(t, err)
+	assert.Len(t, wts, 1)
+	assert.False(t, r.BranchTrack("feat-x").Worktree)
+
+	// prune the removed dir
+	wtPath2 := filepath.Join(t.TempDir(), "detached")
+	assert.NoErr(t, r.AddWorktree(wtPath2, "HEAD", &gitw.WorktreeAddOpts{Detach: true}))
+	assert.NoErr(t, os.RemoveAll(wtPath2))
+
+	out, err := r.PruneWorktrees(&gitw.WorktreePruneOpts{DryRun: true})
+	assert.NoErr(t, err)
+	assert.StrContains(t, out, "detached")
+	wts, _ = r.Worktrees()
+	assert.Len(t, wts, 2)
+
+	_, err = r.PruneWorktrees(nil)
+	assert.NoErr(t, err)
+	wts, _ = r.Worktrees()
+	assert.Len(t, wts, 1)
+}